@@ -12,6 +12,21 @@ type ImageFlags struct {
 	FixedImagesFile existingFileType `group:"images" help:"Use .yaml file to pin image versions. See output of list-images sub-command or read the documentation for details about the output format" exts:"yml,yaml"`
 	UpdateImages    bool             `group:"images" short:"u" help:"This causes kluctl to prefer the latest image found in registries, based on the 'latest_image' filters provided to 'images.get_image(...)' calls. Use this flag if you want to update to the latest versions/tags of all images. '-u' takes precedence over '--fixed-image/--fixed-images-file', meaning that the latest images are used even if an older image is given via fixed images."`
 	OfflineImages   bool             `group:"images" help:"Omit contacting image registries and do not query for latest image tags."`
+	PinDigests      bool             `group:"images" help:"Resolve all images used via 'images.get_image(...)' to their manifest digests and pin them in the form 'image:tag@sha256:...'. This can also be enabled per target via 'pinDigests: true'."`
+	ImageCatalog    existingFileType `group:"images" help:"Use the given image catalog (see 'kluctl images export-catalog') to resolve latest image tags instead of querying image registries. Images missing in the catalog cause an error." exts:"yml,yaml"`
+}
+
+func (args *ImageFlags) LoadImageCatalogFromArgs() (*types.ImageCatalog, error) {
+	if args.ImageCatalog == "" {
+		return nil, nil
+	}
+
+	var ret types.ImageCatalog
+	err := yaml.ReadYamlFile(args.ImageCatalog.String(), &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (args *ImageFlags) LoadFixedImagesFromArgs() ([]types.FixedImage, error) {
@@ -21,8 +21,18 @@ type ReplaceOnErrorFlags struct {
 	ForceReplaceOnError bool `group:"misc" help:"Same as --replace-on-error, but also try to delete and re-create objects. See documentation for more details."`
 }
 
+type AllowProtectedFlags struct {
+	AllowProtected bool `group:"misc" help:"Allow deletion of objects that are protected via the 'kluctl.io/delete-protection' annotation or the 'protectedKinds' project configuration."`
+}
+
+type RemoveFinalizersFlags struct {
+	RemoveFinalizersAfter time.Duration `group:"misc" help:"Forcefully remove the finalizers of objects that are stuck in deletion for longer than the given duration. Removal of finalizers is recorded as a warning. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, finalizers are never removed and stuck objects are reported as errors."`
+}
+
 type HookFlags struct {
-	ReadinessTimeout time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is meant per-object. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
+	ReadinessTimeout  time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is meant per-object. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
+	HookLogs          bool          `group:"misc" help:"Also collect the logs of successful hook Pods and Jobs. Logs of failed hooks are always collected."`
+	AllowCommandHooks bool          `group:"misc" help:"Allow running external hooks of type 'command'. These run local commands with the environment and vars of kluctl, so only enable this for trusted projects (including all included projects)."`
 }
 
 type IgnoreFlags struct {
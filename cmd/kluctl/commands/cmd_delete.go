@@ -6,10 +6,7 @@ import (
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
-	"github.com/kluctl/kluctl/v2/pkg/deployment/utils"
-	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/status"
-	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"os"
 )
@@ -22,6 +19,9 @@ type deleteCmd struct {
 	args.InclusionFlags
 	args.YesFlags
 	args.DryRunFlags
+	args.AllowProtectedFlags
+	args.RemoveFinalizersFlags
+	args.HookFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 
@@ -33,7 +33,13 @@ func (cmd *deleteCmd) Help() string {
 
 WARNING: This command will also delete objects which are not part of your deployment
 project (anymore). It really only decides based on the 'deleteByLabel' labels and does NOT
-take the local target/state into account!`
+take the local target/state into account!
+
+The 'pre-delete' and 'post-delete' hooks (including Helm 'pre-delete' and 'post-delete' hooks)
+of the deployment items that own the deleted objects are executed before and after the deletion.
+
+Objects protected via the 'kluctl.io/delete-protection' annotation or the 'protectedKinds'
+project configuration are skipped, unless '--allow-protected' is passed.`
 }
 
 func (cmd *deleteCmd) Run() error {
@@ -55,12 +61,15 @@ func (cmd *deleteCmd) Run() error {
 		}
 
 		cmd2.OverrideDeleteByLabels = deleteByLabels
+		cmd2.ReadinessTimeout = cmd.ReadinessTimeout
+		cmd2.HookLogs = cmd.HookLogs
+		cmd2.AllowCommandHooks = cmd.AllowCommandHooks
+		cmd2.AllowProtected = cmd.AllowProtected
+		cmd2.RemoveFinalizersAfter = cmd.RemoveFinalizersAfter
 
-		objects, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
-		if err != nil {
-			return err
-		}
-		result, err := confirmedDeleteObjects(ctx.ctx, ctx.targetCtx.SharedContext.K, objects, cmd.DryRun, cmd.Yes)
+		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K, func(refs []k8s2.ObjectRef) error {
+			return confirmDeletion(ctx.ctx, refs, cmd.DryRun, cmd.Yes)
+		})
 		if err != nil {
 			return err
 		}
@@ -75,7 +84,7 @@ func (cmd *deleteCmd) Run() error {
 	})
 }
 
-func confirmedDeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.ObjectRef, dryRun bool, forceYes bool) (*types.CommandResult, error) {
+func confirmDeletion(ctx context.Context, refs []k8s2.ObjectRef, dryRun bool, forceYes bool) error {
 	if len(refs) != 0 {
 		_, _ = os.Stderr.WriteString("The following objects will be deleted:\n")
 		for _, ref := range refs {
@@ -83,10 +92,9 @@ func confirmedDeleteObjects(ctx context.Context, k *k8s.K8sCluster, refs []k8s2.
 		}
 		if !forceYes && !dryRun {
 			if !status.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to delete %d objects?", len(refs))) {
-				return nil, fmt.Errorf("aborted")
+				return fmt.Errorf("aborted")
 			}
 		}
 	}
-
-	return utils.DeleteObjects(k, refs, true)
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"strings"
 )
 
 type deployCmd struct {
@@ -58,8 +59,16 @@ func (cmd *deployCmd) runCmdDeploy(ctx *commandCtx) error {
 	cmd2.ForceReplaceOnError = cmd.ForceReplaceOnError
 	cmd2.AbortOnError = cmd.AbortOnError
 	cmd2.ReadinessTimeout = cmd.ReadinessTimeout
+	cmd2.HookLogs = cmd.HookLogs
+	cmd2.AllowCommandHooks = cmd.AllowCommandHooks
 	cmd2.NoWait = cmd.NoWait
 
+	keys, err := ctx.targetCtx.KluctlProject.LoadImageVerificationKeys()
+	if err != nil {
+		return err
+	}
+	cmd2.ImageVerificationKeys = keys
+
 	cb := cmd.diffResultCb
 	if cmd.Yes || cmd.DryRun {
 		cb = nil
@@ -96,5 +105,17 @@ func (cmd *deployCmd) diffResultCb(diffResult *types.CommandResult) error {
 			return fmt.Errorf("aborted")
 		}
 	}
+	var recreateRefs []string
+	for _, co := range diffResult.ChangedObjects {
+		if co.RequiresRecreation {
+			recreateRefs = append(recreateRefs, co.Ref.String())
+		}
+	}
+	if len(recreateRefs) != 0 {
+		msg := fmt.Sprintf("The following objects require recreation due to changes to immutable fields and will be deleted and re-created:\n  %s\nDo you want to proceed?", strings.Join(recreateRefs, "\n  "))
+		if !status.AskForConfirmation(cliCtx, msg) {
+			return fmt.Errorf("aborted")
+		}
+	}
 	return nil
 }
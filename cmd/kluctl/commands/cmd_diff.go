@@ -17,6 +17,8 @@ type diffCmd struct {
 	args.IgnoreFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
+
+	ShowOwnership bool `group:"misc" help:"Show the field managers of changed objects that own fields which kluctl tries to set"`
 }
 
 func (cmd *diffCmd) Help() string {
@@ -43,6 +45,7 @@ func (cmd *diffCmd) Run() error {
 		cmd2.IgnoreTags = cmd.IgnoreTags
 		cmd2.IgnoreLabels = cmd.IgnoreLabels
 		cmd2.IgnoreAnnotations = cmd.IgnoreAnnotations
+		cmd2.ShowOwnership = cmd.ShowOwnership
 		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
 		if err != nil {
 			return err
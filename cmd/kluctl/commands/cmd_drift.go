@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+)
+
+const (
+	driftExitCodeError   = 1
+	driftExitCodeDrifted = 2
+)
+
+type driftCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+	args.ImageFlags
+	args.IgnoreFlags
+	args.RenderOutputDirFlags
+
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml' or 'json'. Can be specified multiple times."`
+}
+
+func (cmd *driftCmd) Help() string {
+	return `This renders the target, retrieves the remote objects and compares them without modifying
+anything in the cluster. Missing, changed and orphan objects are reported.
+
+The command exits with code 0 if the cluster is in sync, with code 2 if drift was detected and
+with code 1 in case of errors.`
+}
+
+func (cmd *driftCmd) Run() error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
+		cmd2 := commands.NewDriftCommand(ctx.targetCtx.DeploymentCollection)
+		cmd2.IgnoreTags = cmd.IgnoreTags
+		cmd2.IgnoreLabels = cmd.IgnoreLabels
+		cmd2.IgnoreAnnotations = cmd.IgnoreAnnotations
+		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
+		if err != nil {
+			return err
+		}
+		err = outputDriftResult(cmd.OutputFormat, result)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) != 0 {
+			return &exitCodeError{code: driftExitCodeError, err: fmt.Errorf("command failed")}
+		}
+		if result.Drifted {
+			return &exitCodeError{code: driftExitCodeDrifted, err: fmt.Errorf("drift detected")}
+		}
+		return nil
+	})
+}
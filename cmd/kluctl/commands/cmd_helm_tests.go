@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+)
+
+type helmTestCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.DryRunFlags
+	args.HookFlags
+	args.OutputFormatFlags
+	args.RenderOutputDirFlags
+}
+
+func (cmd *helmTestCmd) Help() string {
+	return `Test hooks are applied the same way as other hooks, meaning that they are deleted before
+re-creation and that kluctl waits for them to finish. A test fails if its hook resource does not
+become ready, e.g. because a test Pod exited with an error.`
+}
+
+func (cmd *helmTestCmd) Run() error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		dryRunArgs:           &cmd.DryRunFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
+		cmd2 := commands.NewHelmTestCommand(ctx.targetCtx.DeploymentCollection)
+		cmd2.ReadinessTimeout = cmd.ReadinessTimeout
+		cmd2.HookLogs = cmd.HookLogs
+		cmd2.AllowCommandHooks = cmd.AllowCommandHooks
+
+		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
+		if err != nil {
+			return err
+		}
+		err = outputCommandResult(cmd.OutputFormat, result)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) != 0 {
+			return fmt.Errorf("command failed")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,5 @@
+package commands
+
+type imagesCmd struct {
+	ExportCatalog imagesExportCatalogCmd `cmd:"" help:"Renders the target and exports the tags of all images used via 'images.get_image(...)' into an image catalog"`
+}
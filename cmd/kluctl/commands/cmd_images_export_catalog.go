@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+)
+
+type imagesExportCatalogCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.OutputFlags
+	args.RenderOutputDirFlags
+
+	OfflineKubernetes bool `group:"misc" help:"Run export-catalog in offline mode, meaning that it will not try to connect the target cluster"`
+}
+
+func (cmd *imagesExportCatalogCmd) Help() string {
+	return `The resulting catalog contains all tags found in the image registries for all images
+that are referenced via 'images.get_image(...)'. It can later be passed to other commands
+via '--image-catalog', which allows to resolve 'latest_version' filters in a deterministic
+way and without access to the image registries, e.g. in air-gapped CI environments.`
+}
+
+func (cmd *imagesExportCatalogCmd) Run() error {
+	if cmd.OfflineImages {
+		return fmt.Errorf("--offline-images can not be used when exporting an image catalog")
+	}
+
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		offlineKubernetes:    cmd.OfflineKubernetes,
+	}
+	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
+		return outputYamlResult(cmd.Output, ctx.images.ImageCatalog(), false)
+	})
+}
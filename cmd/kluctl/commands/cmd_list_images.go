@@ -22,7 +22,10 @@ func (cmd *listImagesCmd) Help() string {
 	return `The result is a compatible with yaml files expected by --fixed-images-file.
 
 If fixed images ('-f/--fixed-image') are provided, these are also taken into account,
-as described in the deploy command.`
+as described in the deploy command.
+
+If the target specifies image rewrites, images from plain manifests and Helm charts which
+got rewritten are listed as well. The rewritten image is then available in 'rewrittenImage'.`
 }
 
 func (cmd *listImagesCmd) Run() error {
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+)
+
+type ownershipCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+	args.ImageFlags
+	args.RenderOutputDirFlags
+
+	OutputFormat  []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml' or 'json'. Can be specified multiple times."`
+	OnlyConflicts bool     `group:"misc" help:"Only show fields that are owned by field managers which kluctl will not overwrite"`
+}
+
+func (cmd *ownershipCmd) Help() string {
+	return `This reads the managedFields of all deployed objects and shows, per object, which field managers
+own the fields that kluctl tries to set. Fields marked as conflicting are owned by field managers
+that kluctl will not overwrite, meaning that kluctl will give up these fields when conflicts arise.
+Use 'ignoreForDiff' or the 'kluctl.io/force-apply-field' annotation to handle these fields deliberately.`
+}
+
+func (cmd *ownershipCmd) Run() error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
+		cmd2 := commands.NewOwnershipCommand(ctx.targetCtx.DeploymentCollection)
+		cmd2.OnlyConflicts = cmd.OnlyConflicts
+		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
+		if err != nil {
+			return err
+		}
+		err = outputOwnershipResult(cmd.OutputFormat, result)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) != 0 {
+			return fmt.Errorf("command failed")
+		}
+		return nil
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 )
 
 type pruneCmd struct {
@@ -14,6 +15,9 @@ type pruneCmd struct {
 	args.InclusionFlags
 	args.YesFlags
 	args.DryRunFlags
+	args.AllowProtectedFlags
+	args.RemoveFinalizersFlags
+	args.HookFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 }
@@ -23,7 +27,13 @@ func (cmd *pruneCmd) Help() string {
 
   1. Search the cluster for all objects match 'commonLabels', as configured in 'deployment.yaml'
   2. Render the local target and list all objects.
-  3. Remove all objects from the list of 1. that are part of the list in 2.`
+  3. Remove all objects from the list of 1. that are part of the list in 2.
+
+The 'pre-prune' and 'post-prune' hooks (including Helm 'pre-delete' and 'post-delete' hooks)
+of the deployment items that own the pruned objects are executed before and after the deletion.
+
+Objects protected via the 'kluctl.io/delete-protection' annotation or the 'protectedKinds'
+project configuration are skipped, unless '--allow-protected' is passed.`
 }
 
 func (cmd *pruneCmd) Run() error {
@@ -43,11 +53,14 @@ func (cmd *pruneCmd) Run() error {
 
 func (cmd *pruneCmd) runCmdPrune(ctx *commandCtx) error {
 	cmd2 := commands.NewPruneCommand(ctx.targetCtx.DeploymentCollection)
-	objects, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
-	if err != nil {
-		return err
-	}
-	result, err := confirmedDeleteObjects(ctx.ctx, ctx.targetCtx.SharedContext.K, objects, cmd.DryRun, cmd.Yes)
+	cmd2.ReadinessTimeout = cmd.ReadinessTimeout
+	cmd2.HookLogs = cmd.HookLogs
+	cmd2.AllowCommandHooks = cmd.AllowCommandHooks
+	cmd2.AllowProtected = cmd.AllowProtected
+	cmd2.RemoveFinalizersAfter = cmd.RemoveFinalizersAfter
+	result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K, func(refs []k8s2.ObjectRef) error {
+		return confirmDeletion(ctx.ctx, refs, cmd.DryRun, cmd.Yes)
+	})
 	if err != nil {
 		return err
 	}
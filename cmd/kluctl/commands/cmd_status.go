@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+)
+
+type statusCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml' or 'json'. Can be specified multiple times."`
+}
+
+func (cmd *statusCmd) Help() string {
+	return `This lists all deployment items of the target together with the objects they own in the cluster, their
+health, the images that are actually running and the time at which kluctl applied them the last time. Objects
+that carry the target's commonLabels but do not belong to any deployment item are listed as orphan objects.
+
+Deployment items are not rendered, meaning that templates requiring secrets are not needed to run this command.
+Objects are instead found via the commonLabels and the 'kluctl.io/kustomize_dir' annotation.`
+}
+
+func (cmd *statusCmd) Run() error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:   cmd.ProjectFlags,
+		targetFlags:    cmd.TargetFlags,
+		argsFlags:      cmd.ArgsFlags,
+		inclusionFlags: cmd.InclusionFlags,
+		skipRender:     true,
+	}
+	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
+		cmd2 := commands.NewStatusCommand(ctx.targetCtx.DeploymentCollection)
+		result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
+		if err != nil {
+			return err
+		}
+		err = outputStatusResult(cmd.OutputFormat, result)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) != 0 {
+			return fmt.Errorf("command failed")
+		}
+		return nil
+	})
+}
@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"net"
+	"net/http"
 	"os"
 	"time"
 )
@@ -19,12 +25,20 @@ type validateCmd struct {
 	Wait             time.Duration `group:"misc" help:"Wait for the given amount of time until the deployment validates"`
 	Sleep            time.Duration `group:"misc" help:"Sleep duration between validation attempts" default:"5s"`
 	WarningsAsErrors bool          `group:"misc" help:"Consider warnings as failures"`
+
+	Watch            bool   `group:"misc" help:"Continuously watch all objects and print health transitions and related events. The command runs until it gets interrupted."`
+	WatchHttpAddress string `group:"misc" help:"When --watch is used, serve the current validation result as JSON on the given address, e.g. 127.0.0.1:8080."`
 }
 
 func (cmd *validateCmd) Help() string {
 	return `This means that all objects are retrieved from the cluster and checked for readiness.
 
-TODO: This needs to be better documented!`
+The result also contains the aggregated health (Healthy, Progressing, Missing or Degraded) of
+each kustomize deployment, together with all objects that are not healthy.
+
+When --watch is passed, the command keeps running after the initial validation and uses the
+watch API of Kubernetes to re-validate objects whenever these change. Each health transition
+is printed, together with recent warning events of objects that are not healthy.`
 }
 
 func (cmd *validateCmd) Run() error {
@@ -38,6 +52,9 @@ func (cmd *validateCmd) Run() error {
 	return withProjectCommandContext(ptArgs, func(ctx *commandCtx) error {
 		startTime := time.Now()
 		cmd2 := commands.NewValidateCommand(ctx.ctx, ctx.targetCtx.DeploymentCollection)
+		if cmd.Watch {
+			return cmd.runWatch(ctx, cmd2)
+		}
 		for true {
 			result, err := cmd2.Run(ctx.ctx, ctx.targetCtx.SharedContext.K)
 			if err != nil {
@@ -75,3 +92,77 @@ func (cmd *validateCmd) Run() error {
 		return nil
 	})
 }
+
+func (cmd *validateCmd) runWatch(ctx *commandCtx, cmd2 *commands.ValidateCommand) error {
+	initialCb := func(result *types.ValidateResult) error {
+		err := outputValidateResult(cmd.Output, result)
+		if err != nil {
+			return err
+		}
+		if cmd.WatchHttpAddress != "" {
+			err = serveValidateResult(ctx.ctx, cmd.WatchHttpAddress, cmd2.GetWatchResult)
+			if err != nil {
+				return err
+			}
+		}
+		_, _ = os.Stderr.WriteString("Watching for changes...\n")
+		return nil
+	}
+	transitionCb := func(t types.ValidateTransition) {
+		status.Flush(ctx.ctx)
+		_, _ = os.Stdout.WriteString(formatValidateTransition(t))
+	}
+	return cmd2.Watch(ctx.ctx, ctx.targetCtx.SharedContext.K, initialCb, transitionCb)
+}
+
+func formatValidateTransition(t types.ValidateTransition) string {
+	oldHealth := t.OldHealth
+	if oldHealth == "" {
+		oldHealth = "Unknown"
+	}
+	s := fmt.Sprintf("%s %s: %s -> %s", time.Now().Format(time.RFC3339), t.Ref.String(), oldHealth, t.NewHealth)
+	if t.Message != "" {
+		s += fmt.Sprintf(" (%s)", t.Message)
+	}
+	s += "\n"
+	for _, e := range t.Events {
+		s += fmt.Sprintf("  Event: %s\n", e)
+	}
+	return s
+}
+
+// newValidateResultHandler returns a http handler that responds with the result returned by getResult as JSON
+func newValidateResultHandler(getResult func() *types.ValidateResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s, err := yaml.WriteJsonString(getResult())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(s))
+	})
+	return mux
+}
+
+// serveValidateResult serves the result returned by getResult as JSON until the context is cancelled
+func serveValidateResult(ctx context.Context, address string, getResult func() *types.ValidateResult) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: newValidateResultHandler(getResult)}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go func() {
+		_ = server.Serve(l)
+	}()
+
+	status.Info(ctx, "Serving validation result on http://%s", l.Addr().String())
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateResultHandler(t *testing.T) {
+	result := &types.ValidateResult{Ready: false}
+	h := newValidateResultHandler(func() *types.ValidateResult {
+		return result
+	})
+
+	get := func() *types.ValidateResult {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var r types.ValidateResult
+		err := yaml.ReadYamlString(rec.Body.String(), &r)
+		assert.NoError(t, err)
+		return &r
+	}
+
+	assert.False(t, get().Ready)
+
+	// the handler must always serve the latest result
+	result = &types.ValidateResult{
+		Ready: true,
+		Health: []types.DeploymentItemHealth{
+			{Dir: "app", Health: types.HealthHealthy},
+		},
+	}
+	r := get()
+	assert.True(t, r.Ready)
+	assert.Equal(t, result.Health, r.Health)
+}
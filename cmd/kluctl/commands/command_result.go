@@ -43,6 +43,21 @@ func formatCommandResultText(cr *types.CommandResult) string {
 				buf.WriteString("\n")
 			}
 			prettyChanges(buf, co.Ref, co.Changes)
+			if len(co.Ownership) != 0 {
+				buf.WriteString("\n")
+				prettyOwnership(buf, co.Ref, co.Ownership)
+			}
+		}
+
+		var recreateRefs []k8s.ObjectRef
+		for _, co := range cr.ChangedObjects {
+			if co.RequiresRecreation {
+				recreateRefs = append(recreateRefs, co.Ref)
+			}
+		}
+		if len(recreateRefs) != 0 {
+			buf.WriteString("\nObjects requiring recreation (changes to immutable fields):\n")
+			prettyObjectRefs(buf, recreateRefs)
 		}
 	}
 
@@ -51,19 +66,37 @@ func formatCommandResultText(cr *types.CommandResult) string {
 		prettyObjectRefs(buf, cr.DeletedObjects)
 	}
 
+	if len(cr.ProtectedObjects) != 0 {
+		buf.WriteString("\nProtected objects (not deleted):\n")
+		prettyObjectRefs(buf, cr.ProtectedObjects)
+	}
+
+	if len(cr.RolledBackObjects) != 0 {
+		buf.WriteString("\nRolled back objects:\n")
+		for _, o := range cr.RolledBackObjects {
+			buf.WriteString(fmt.Sprintf("  %s (%s)\n", o.Ref.String(), o.Action))
+		}
+	}
+
 	if len(cr.HookObjects) != 0 {
 		buf.WriteString("\nApplied hooks:\n")
-		var refs []k8s.ObjectRef
 		for _, o := range cr.HookObjects {
-			refs = append(refs, o.Ref)
+			prettyObjectRefs(buf, []k8s.ObjectRef{o.Ref})
+			if o.Logs != "" {
+				prettyLogs(buf, o.Logs)
+			}
 		}
-		prettyObjectRefs(buf, refs)
 	}
 	if len(cr.OrphanObjects) != 0 {
 		buf.WriteString("\nOrphan objects:\n")
 		prettyObjectRefs(buf, cr.OrphanObjects)
 	}
 
+	if len(cr.Health) != 0 {
+		buf.WriteString("\nHealth:\n")
+		prettyHealth(buf, cr.Health)
+	}
+
 	if len(cr.Errors) != 0 {
 		buf.WriteString("\nErrors:\n")
 		prettyErrors(buf, cr.Errors)
@@ -81,6 +114,22 @@ func prettyObjectRefs(buf io.StringWriter, refs []k8s.ObjectRef) {
 func prettyErrors(buf io.StringWriter, errors []types.DeploymentError) {
 	for _, e := range errors {
 		_, _ = buf.WriteString(fmt.Sprintf("  %s: %s\n", e.Ref.String(), e.Error))
+		if e.Logs != "" {
+			prettyLogs(buf, e.Logs)
+		}
+		if e.Events != "" {
+			_, _ = buf.WriteString("    Events:\n")
+			for _, l := range strings.Split(e.Events, "\n") {
+				_, _ = buf.WriteString(fmt.Sprintf("      %s\n", l))
+			}
+		}
+	}
+}
+
+func prettyLogs(buf io.StringWriter, logs string) {
+	_, _ = buf.WriteString("    Logs:\n")
+	for _, l := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+		_, _ = buf.WriteString(fmt.Sprintf("      %s\n", l))
 	}
 }
 
@@ -97,6 +146,31 @@ func prettyChanges(buf io.StringWriter, ref k8s.ObjectRef, changes []types.Chang
 	_, _ = buf.WriteString(s)
 }
 
+func prettyOwnership(buf io.StringWriter, ref k8s.ObjectRef, fields []types.FieldOwnership) {
+	_, _ = buf.WriteString(fmt.Sprintf("Field ownership for object %s\n", ref.String()))
+
+	var t utils.PrettyTable
+	t.AddRow("Path", "Owners", "Conflict")
+
+	for _, f := range fields {
+		var owners []string
+		for _, o := range f.Owners {
+			if o.Operation != "" {
+				owners = append(owners, fmt.Sprintf("%s (%s)", o.Manager, o.Operation))
+			} else {
+				owners = append(owners, o.Manager)
+			}
+		}
+		conflict := ""
+		if f.Conflict {
+			conflict = "yes"
+		}
+		t.AddRow(f.JsonPath, strings.Join(owners, "\n"), conflict)
+	}
+	s := t.Render([]int{60})
+	_, _ = buf.WriteString(s)
+}
+
 func formatCommandResultYaml(cr *types.CommandResult) (string, error) {
 	b, err := yaml.WriteYamlString(cr)
 	if err != nil {
@@ -116,6 +190,157 @@ func formatCommandResult(cr *types.CommandResult, format string) (string, error)
 	}
 }
 
+func formatDriftResultText(dr *types.DriftResult) string {
+	buf := bytes.NewBuffer(nil)
+
+	if len(dr.Warnings) != 0 {
+		buf.WriteString("\nWarnings:\n")
+		prettyErrors(buf, dr.Warnings)
+	}
+
+	if len(dr.MissingObjects) != 0 {
+		buf.WriteString("\nMissing objects:\n")
+		prettyObjectRefs(buf, dr.MissingObjects)
+	}
+	if len(dr.ChangedObjects) != 0 {
+		buf.WriteString("\nChanged objects:\n")
+		var refs []k8s.ObjectRef
+		for _, co := range dr.ChangedObjects {
+			refs = append(refs, co.Ref)
+		}
+		prettyObjectRefs(buf, refs)
+
+		buf.WriteString("\n")
+		for i, co := range dr.ChangedObjects {
+			if i != 0 {
+				buf.WriteString("\n")
+			}
+			prettyChanges(buf, co.Ref, co.Changes)
+		}
+	}
+	if len(dr.DeletedObjects) != 0 {
+		buf.WriteString("\nObjects marked for deletion:\n")
+		prettyObjectRefs(buf, dr.DeletedObjects)
+	}
+	if len(dr.OrphanObjects) != 0 {
+		buf.WriteString("\nOrphan objects:\n")
+		prettyObjectRefs(buf, dr.OrphanObjects)
+	}
+
+	if len(dr.Errors) != 0 {
+		buf.WriteString("\nErrors:\n")
+		prettyErrors(buf, dr.Errors)
+	}
+
+	if dr.Drifted {
+		buf.WriteString("\nDrift detected\n")
+	} else {
+		buf.WriteString("\nIn sync\n")
+	}
+
+	return buf.String()
+}
+
+func formatDriftResult(dr *types.DriftResult, format string) (string, error) {
+	switch format {
+	case "text":
+		return formatDriftResultText(dr), nil
+	case "yaml":
+		return yaml.WriteYamlString(dr)
+	case "json":
+		return yaml.WriteJsonString(dr)
+	default:
+		return "", fmt.Errorf("invalid format: %s", format)
+	}
+}
+
+func formatOwnershipResultText(or *types.OwnershipResult) string {
+	buf := bytes.NewBuffer(nil)
+
+	if len(or.Warnings) != 0 {
+		buf.WriteString("\nWarnings:\n")
+		prettyErrors(buf, or.Warnings)
+	}
+
+	for _, o := range or.Objects {
+		buf.WriteString("\n")
+		prettyOwnership(buf, o.Ref, o.Fields)
+	}
+
+	if len(or.Errors) != 0 {
+		buf.WriteString("\nErrors:\n")
+		prettyErrors(buf, or.Errors)
+	}
+
+	return buf.String()
+}
+
+func formatOwnershipResult(or *types.OwnershipResult, format string) (string, error) {
+	switch format {
+	case "text":
+		return formatOwnershipResultText(or), nil
+	case "yaml":
+		return yaml.WriteYamlString(or)
+	case "json":
+		return yaml.WriteJsonString(or)
+	default:
+		return "", fmt.Errorf("invalid format: %s", format)
+	}
+}
+
+func prettyStatus(buf io.StringWriter, items []types.DeploymentItemStatus) {
+	var t utils.PrettyTable
+	t.AddRow("Deployment", "Object", "Health", "Last applied", "Images", "Message")
+
+	for _, item := range items {
+		t.AddRow(item.Dir, "", item.Health, item.LastApplied, "", "")
+		for _, o := range item.Objects {
+			t.AddRow("", o.Ref.String(), o.Health, o.LastApplied, strings.Join(o.Images, "\n"), o.Message)
+		}
+	}
+	s := t.Render([]int{40, 60, 12, 20, 60})
+	_, _ = buf.WriteString(s)
+}
+
+func formatStatusResultText(sr *types.StatusResult) string {
+	buf := bytes.NewBuffer(nil)
+
+	if len(sr.Warnings) != 0 {
+		buf.WriteString("\nWarnings:\n")
+		prettyErrors(buf, sr.Warnings)
+	}
+
+	if len(sr.Items) != 0 {
+		buf.WriteString("\nDeployments:\n")
+		prettyStatus(buf, sr.Items)
+	}
+
+	if len(sr.OrphanObjects) != 0 {
+		buf.WriteString("\nOrphan objects:\n")
+		prettyObjectRefs(buf, sr.OrphanObjects)
+	}
+
+	if len(sr.Errors) != 0 {
+		buf.WriteString("\nErrors:\n")
+		prettyErrors(buf, sr.Errors)
+	}
+
+	return buf.String()
+}
+
+func formatStatusResult(sr *types.StatusResult, format string) (string, error) {
+	switch format {
+	case "text":
+		return formatStatusResultText(sr), nil
+	case "yaml":
+		return yaml.WriteYamlString(sr)
+	case "json":
+		return yaml.WriteJsonString(sr)
+	default:
+		return "", fmt.Errorf("invalid format: %s", format)
+	}
+}
+
 func prettyValidationResults(buf io.StringWriter, results []types.ValidateResultEntry) {
 	var t utils.PrettyTable
 	t.AddRow("Object", "Message")
@@ -127,6 +352,27 @@ func prettyValidationResults(buf io.StringWriter, results []types.ValidateResult
 	_, _ = buf.WriteString(s)
 }
 
+func prettyHealth(buf io.StringWriter, health []types.DeploymentItemHealth) {
+	var t utils.PrettyTable
+	t.AddRow("Deployment", "Health", "Object", "Message")
+
+	for _, h := range health {
+		if len(h.Objects) == 0 {
+			t.AddRow(h.Dir, h.Health, "", "")
+			continue
+		}
+		for i, o := range h.Objects {
+			if i == 0 {
+				t.AddRow(h.Dir, h.Health, fmt.Sprintf("%s (%s)", o.Ref.String(), o.Health), o.Message)
+			} else {
+				t.AddRow("", "", fmt.Sprintf("%s (%s)", o.Ref.String(), o.Health), o.Message)
+			}
+		}
+	}
+	s := t.Render([]int{40, 12, 60})
+	_, _ = buf.WriteString(s)
+}
+
 func formatValidateResultText(vr *types.ValidateResult) string {
 	buf := bytes.NewBuffer(nil)
 
@@ -150,6 +396,14 @@ func formatValidateResultText(vr *types.ValidateResult) string {
 		buf.WriteString("Results:\n")
 		prettyValidationResults(buf, vr.Results)
 	}
+
+	if len(vr.Health) != 0 {
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Health:\n")
+		prettyHealth(buf, vr.Health)
+	}
 	return buf.String()
 }
 
@@ -212,6 +466,30 @@ func outputValidateResult(output []string, vr *types.ValidateResult) error {
 	})
 }
 
+func outputDriftResult(output []string, dr *types.DriftResult) error {
+	status.Flush(cliCtx)
+
+	return outputHelper(output, func(format string) (string, error) {
+		return formatDriftResult(dr, format)
+	})
+}
+
+func outputOwnershipResult(output []string, or *types.OwnershipResult) error {
+	status.Flush(cliCtx)
+
+	return outputHelper(output, func(format string) (string, error) {
+		return formatOwnershipResult(or, format)
+	})
+}
+
+func outputStatusResult(output []string, sr *types.StatusResult) error {
+	status.Flush(cliCtx)
+
+	return outputHelper(output, func(format string) (string, error) {
+		return formatStatusResult(sr, format)
+	})
+}
+
 func outputYamlResult(output []string, result interface{}, multiDoc bool) error {
 	status.Flush(cliCtx)
 
@@ -17,6 +17,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -53,14 +54,19 @@ type cli struct {
 	Delete            deleteCmd            `cmd:"" help:"Delete a target (or parts of it) from the corresponding cluster"`
 	Deploy            deployCmd            `cmd:"" help:"Deploys a target to the corresponding cluster"`
 	Diff              diffCmd              `cmd:"" help:"Perform a diff between the locally rendered target and the already deployed target"`
+	Drift             driftCmd             `cmd:"" help:"Checks if the already deployed target has drifted from the locally rendered target"`
 	HelmPull          helmPullCmd          `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and pulls the specified Helm charts"`
+	HelmTest          helmTestCmd          `cmd:"" help:"Runs the test hooks ('helm.sh/hook: test') of all Helm charts in the target"`
 	HelmUpdate        helmUpdateCmd        `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and checks for new available versions"`
+	Images            imagesCmd            `cmd:"" help:"Image related sub-commands"`
 	ListImages        listImagesCmd        `cmd:"" help:"Renders the target and outputs all images used via 'images.get_image(...)"`
 	ListTargets       listTargetsCmd       `cmd:"" help:"Outputs a yaml list with all target, including dynamic targets"`
+	Ownership         ownershipCmd         `cmd:"" help:"Shows which field managers own the fields that kluctl tries to set"`
 	PokeImages        pokeImagesCmd        `cmd:"" help:"Replace all images in target"`
 	Prune             pruneCmd             `cmd:"" help:"Searches the target cluster for prunable objects and deletes them"`
 	Render            renderCmd            `cmd:"" help:"Renders all resources and configuration files"`
 	Seal              sealCmd              `cmd:"" help:"Seal secrets based on target's sealingConfig"`
+	Status            statusCmd            `cmd:"" help:"Summarizes the live state of a target in the cluster"`
 	Validate          validateCmd          `cmd:"" help:"Validates the already deployed deployment"`
 	Flux              fluxCmd              `cmd:"" help:"Flux sub-commands"`
 
@@ -214,6 +220,20 @@ func initViper() {
 	}
 }
 
+// exitCodeError allows commands to exit with a specific exit code instead of the default (1)
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 func Execute() {
 	colorable.EnableColorsStdout(nil)
 
@@ -258,6 +278,10 @@ composed of multiple smaller parts (Helm/Kustomize/...) in a manageable and unif
 	if err != nil {
 		status.Error(cliCtx, "%s", err.Error())
 		sh.Stop()
+		var ece *exitCodeError
+		if errors.As(err, &ece) {
+			os.Exit(ece.code)
+		}
 		os.Exit(1)
 	}
 	sh.Stop()
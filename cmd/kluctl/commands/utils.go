@@ -91,6 +91,8 @@ type projectTargetCommandArgs struct {
 	forSeal           bool
 	forCompletion     bool
 	offlineKubernetes bool
+	// skipRender skips rendering of deployment items, meaning that DeploymentItem.Objects will be empty
+	skipRender bool
 }
 
 type commandCtx struct {
@@ -120,6 +122,15 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		return err
 	}
 	images.PrependFixedImages(fixedImages)
+	images.SetPinDigests(args.imageFlags.PinDigests)
+
+	imageCatalog, err := args.imageFlags.LoadImageCatalogFromArgs()
+	if err != nil {
+		return err
+	}
+	if imageCatalog != nil {
+		images.SetImageCatalog(imageCatalog)
+	}
 
 	inclusion, err := args.inclusionFlags.ParseInclusionFromArgs()
 	if err != nil {
@@ -170,7 +181,7 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		return err
 	}
 
-	if !args.forSeal && !args.forCompletion {
+	if !args.forSeal && !args.forCompletion && !args.skipRender {
 		err = targetCtx.DeploymentCollection.Prepare()
 		if err != nil {
 			return err
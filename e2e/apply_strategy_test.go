@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func prepareApplyStrategyTest(t *testing.T, name string, strategy string) *testProject {
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, name)
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{"a": "v1"}, resourceOpts{
+		name:        "cm",
+		namespace:   p.projectName,
+		annotations: map[string]string{"kluctl.io/apply-strategy": strategy},
+	})
+	return p
+}
+
+func updateConfigMapValue(p *testProject, value string) {
+	p.updateYaml("cm/configmap-cm.yml", func(o *uo.UnstructuredObject) error {
+		return o.SetNestedField(value, "data", "a")
+	}, "")
+}
+
+func TestApplyStrategyCreateOnly(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+	p := prepareApplyStrategyTest(t, "apply-strategy-create-only", "create-only")
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v1", "data", "a")
+
+	// existing objects are not touched
+	updateConfigMapValue(p, "v2")
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v1", "data", "a")
+
+	// but created if missing
+	err := k.DynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).
+		Namespace(p.projectName).
+		Delete(context.Background(), "cm", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v2", "data", "a")
+}
+
+func TestApplyStrategyReplace(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+	p := prepareApplyStrategyTest(t, "apply-strategy-replace", "replace")
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v1", "data", "a")
+
+	// add a field via another field manager, which would be retained by server-side apply
+	_, err := k.DynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).
+		Namespace(p.projectName).
+		Patch(context.Background(), "cm", types.MergePatchType, []byte(`{"data":{"b":"x"}}`), metav1.PatchOptions{
+			FieldManager: "other",
+		})
+	assert.NoError(t, err)
+
+	// replacing the object removes all fields that are not part of the deployment
+	updateConfigMapValue(p, "v2")
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), map[string]interface{}{"a": "v2"}, "data")
+}
@@ -0,0 +1,59 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"os/exec"
+	"testing"
+)
+
+func getExitCode(t *testing.T, err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ee.ExitCode()
+}
+
+func TestDriftExitCodes(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, "drift-exit-codes")
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{"a": "v1"}, resourceOpts{
+		name:      "cm",
+		namespace: p.projectName,
+	})
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+
+	// in sync
+	_, _, err := p.Kluctl("drift", "-t", "test")
+	assert.Equal(t, 0, getExitCode(t, err))
+
+	// drifted
+	_, err = k.DynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).
+		Namespace(p.projectName).
+		Patch(context.Background(), "cm", types.MergePatchType, []byte(`{"data":{"a":"v2"}}`), metav1.PatchOptions{})
+	assert.NoError(t, err)
+	stdout, _, err := p.Kluctl("drift", "-t", "test", "-o", "text")
+	assert.Equal(t, 2, getExitCode(t, err))
+	assert.Contains(t, stdout, "cm")
+
+	// error
+	_, _, err = p.Kluctl("drift", "-t", "does-not-exist")
+	assert.Equal(t, 1, getExitCode(t, err))
+}
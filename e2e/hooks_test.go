@@ -3,12 +3,21 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"path/filepath"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"testing"
+	"time"
 
 	test_utils "github.com/kluctl/kluctl/v2/internal/test-utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
@@ -212,3 +221,371 @@ func TestHooksPrePostDeploy2(t *testing.T) {
 	t.Parallel()
 	doTestHooksPrePostDeploy(t, "pre-post-deploy2", "pre-deploy-initial,pre-deploy-upgrade,post-deploy-initial,post-deploy-upgrade")
 }
+
+func (s *hooksTestContext) removeKustomizeResource(dir string, name string) {
+	s.p.updateKustomizeDeployment(dir, func(o *uo.UnstructuredObject, wt *git.Worktree) error {
+		l, _, _ := o.GetNestedList("resources")
+		var newList []interface{}
+		for _, x := range l {
+			if x != name {
+				newList = append(newList, x)
+			}
+		}
+		return o.SetNestedField(newList, "resources")
+	})
+}
+
+func TestHooksPrePrune(t *testing.T) {
+	t.Parallel()
+	s := prepareHookTestProject(t, "pre-prune", "pre-prune", "")
+	s.addConfigMap("hook", resourceOpts{name: "cm2", namespace: s.p.projectName})
+	s.ensureHookExecuted("cm1", "cm2")
+
+	s.removeKustomizeResource("hook", "cm2.yml")
+	s.clearSeenConfigmaps()
+	s.p.KluctlMust("prune", "--yes", "-t", "test")
+	assert.Contains(t, s.seenConfigMaps, "hook1")
+	assertConfigMapExists(t, s.k, s.p.projectName, "cm1")
+	assertConfigMapNotExists(t, s.k, s.p.projectName, "cm2")
+}
+
+func TestHooksPostPrune(t *testing.T) {
+	t.Parallel()
+	s := prepareHookTestProject(t, "post-prune", "post-prune", "")
+	s.addConfigMap("hook", resourceOpts{name: "cm2", namespace: s.p.projectName})
+	s.ensureHookExecuted("cm1", "cm2")
+
+	s.removeKustomizeResource("hook", "cm2.yml")
+	s.clearSeenConfigmaps()
+	s.p.KluctlMust("prune", "--yes", "-t", "test")
+	assert.Contains(t, s.seenConfigMaps, "hook1")
+	assertConfigMapExists(t, s.k, s.p.projectName, "cm1")
+	assertConfigMapNotExists(t, s.k, s.p.projectName, "cm2")
+}
+
+func TestHooksPostDelete(t *testing.T) {
+	t.Parallel()
+	s := prepareHookTestProject(t, "post-delete", "post-delete", "")
+	s.ensureHookExecuted("cm1")
+
+	s.clearSeenConfigmaps()
+	s.p.KluctlMust("delete", "--yes", "-t", "test")
+	assert.Contains(t, s.seenConfigMaps, "hook1")
+	assertConfigMapNotExists(t, s.k, s.p.projectName, "cm1")
+}
+
+func (s *hooksTestContext) addFailingConfigMap(dir string, name string) {
+	// the API server rejects data keys with spaces, causing the deployment item to fail
+	o := createConfigMapObject(map[string]string{"invalid key": "x"}, resourceOpts{name: name, namespace: s.p.projectName})
+	s.p.addKustomizeResources(dir, []kustomizeResource{
+		{fmt.Sprintf("%s.yml", name), "", o},
+	})
+}
+
+func doTestHooksOnDeployFailure(t *testing.T, name string, args ...string) {
+	s := prepareHookTestProject(t, name, "on-deploy-failure", "")
+	s.ensureHookExecuted("cm1")
+
+	s.addFailingConfigMap("hook", "cm-fail")
+	s.clearSeenConfigmaps()
+	_, _, err := s.p.Kluctl(append([]string{"deploy", "--yes", "-t", "test"}, args...)...)
+	assert.Error(t, err)
+	assert.Contains(t, s.seenConfigMaps, "hook1")
+	assertConfigMapExists(t, s.k, s.p.projectName, "hook1")
+}
+
+func TestHooksOnDeployFailure(t *testing.T) {
+	t.Parallel()
+	doTestHooksOnDeployFailure(t, "on-deploy-failure")
+}
+
+func TestHooksOnDeployFailureAborted(t *testing.T) {
+	t.Parallel()
+	// failure hooks must also run when the deployment got aborted
+	doTestHooksOnDeployFailure(t, "on-deploy-failure-aborted", "--abort-on-error")
+}
+
+func TestHooksDeleteOnlyOwningItem(t *testing.T) {
+	t.Parallel()
+	s := prepareHookTestProject(t, "delete-owner", "pre-delete", "")
+
+	// the second item uses Helm hooks, which must be mapped to the kluctl delete hooks
+	s.p.addKustomizeDeployment("other", nil, nil)
+	s.addConfigMap("other", resourceOpts{name: "cm2", namespace: s.p.projectName})
+	s.addHookConfigMap("other", resourceOpts{name: "hook2", namespace: s.p.projectName}, true, "pre-delete", "")
+	s.addHookConfigMap("other", resourceOpts{name: "hook3", namespace: s.p.projectName}, true, "post-delete", "")
+	s.ensureHookExecuted("cm1", "cm2")
+
+	// only the hooks of the item that owns the deleted objects must be executed
+	s.clearSeenConfigmaps()
+	s.p.KluctlMust("delete", "--yes", "-t", "test", "--include-deployment-dir", "other")
+	assert.Contains(t, s.seenConfigMaps, "hook2")
+	assert.Contains(t, s.seenConfigMaps, "hook3")
+	assert.NotContains(t, s.seenConfigMaps, "hook1")
+	assertConfigMapExists(t, s.k, s.p.projectName, "cm1")
+	assertConfigMapNotExists(t, s.k, s.p.projectName, "cm2")
+}
+
+func (s *hooksTestContext) addTestPod(dir string, name string, hook string) {
+	o := createCoreV1Object("Pod", resourceOpts{
+		name:        name,
+		namespace:   s.p.projectName,
+		annotations: map[string]string{"helm.sh/hook": hook},
+	})
+	_ = o.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"name":  "test",
+			"image": "busybox",
+		},
+	}, "spec", "containers")
+	_ = o.SetNestedField("Never", "spec", "restartPolicy")
+	s.p.addKustomizeResources(dir, []kustomizeResource{
+		{fmt.Sprintf("%s.yml", name), "", o},
+	})
+}
+
+// completeTestPod waits for the given test Pod to be created and then sets its status to emulate a finished test, as
+// the test cluster does not run any Pods
+func (s *hooksTestContext) completeTestPod(name string, failed bool) {
+	var pod *uo.UnstructuredObject
+	for i := 0; i < 60; i++ {
+		x, err := s.k.Get(corev1.SchemeGroupVersion.WithResource("pods"), s.p.projectName, name)
+		if err == nil {
+			pod = x
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if pod == nil {
+		s.t.Errorf("test pod %s was not created", name)
+		return
+	}
+
+	terminateReason := "Completed"
+	if failed {
+		terminateReason = "Error"
+	}
+	_ = pod.SetNestedField("Succeeded", "status", "phase")
+	_ = pod.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"type":   "Ready",
+			"status": "False",
+			"reason": "PodCompleted",
+		},
+	}, "status", "conditions")
+	_ = pod.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"name":  "test",
+			"image": "busybox",
+			"ready": false,
+			"state": map[string]interface{}{
+				"terminated": map[string]interface{}{
+					"reason":   terminateReason,
+					"exitCode": int64(0),
+				},
+			},
+		},
+	}, "status", "containerStatuses")
+	if failed {
+		_ = pod.SetNestedField("Failed", "status", "phase")
+		_ = pod.SetNestedField(int64(1), "status", "containerStatuses", 0, "state", "terminated", "exitCode")
+	}
+
+	_, err := s.k.DynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("pods")).
+		Namespace(s.p.projectName).
+		UpdateStatus(context.Background(), pod.ToUnstructured(), metav1.UpdateOptions{})
+	if err != nil {
+		s.t.Error(err)
+	}
+}
+
+func (s *hooksTestContext) runHelmTest(failedPods map[string]bool, pods ...string) error {
+	errCh := make(chan error)
+	go func() {
+		_, _, err := s.p.Kluctl("helm-test", "-t", "test", "--readiness-timeout", "30s")
+		errCh <- err
+	}()
+	for _, name := range pods {
+		s.completeTestPod(name, failedPods[name])
+	}
+	return <-errCh
+}
+
+// createDefaultServiceAccount creates the default ServiceAccount, which is required by Pods but not created
+// automatically in the test cluster
+func (s *hooksTestContext) createDefaultServiceAccount() {
+	var sa unstructured.Unstructured
+	sa.SetName("default")
+	_, err := s.k.DynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("serviceaccounts")).
+		Namespace(s.p.projectName).
+		Create(context.Background(), &sa, metav1.CreateOptions{})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func prepareHelmTestProject(t *testing.T, name string) *hooksTestContext {
+	s := prepareHookTestProject(t, name, "post-deploy", "")
+	s.createDefaultServiceAccount()
+
+	s.addTestPod("hook", "test1", "test")
+	s.addTestPod("hook", "test2", "test-success")
+	return s
+}
+
+func TestHelmTest(t *testing.T) {
+	t.Parallel()
+	s := prepareHelmTestProject(t, "helm-test")
+
+	// test hooks must not be applied while deploying
+	s.p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, s.k, s.p.projectName, "hook1")
+	_, err := s.k.Get(corev1.SchemeGroupVersion.WithResource("pods"), s.p.projectName, "test1")
+	assert.True(t, errors.IsNotFound(err))
+
+	err = s.runHelmTest(nil, "test1", "test2")
+	assert.NoError(t, err)
+}
+
+func TestHelmTestFailed(t *testing.T) {
+	t.Parallel()
+	s := prepareHelmTestProject(t, "helm-test-failed")
+
+	s.p.KluctlMust("deploy", "--yes", "-t", "test")
+
+	err := s.runHelmTest(map[string]bool{"test2": true}, "test1", "test2")
+	assert.Error(t, err)
+}
+
+func (s *hooksTestContext) addHookJob(dir string, name string, hook string) {
+	o := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+spec:
+  backoffLimit: 0
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: job
+        image: busybox
+        terminationMessagePolicy: FallbackToLogsOnError
+`)
+	mergeMetadata(o, resourceOpts{
+		name:        name,
+		namespace:   s.p.projectName,
+		annotations: map[string]string{"kluctl.io/hook": hook},
+	})
+	s.p.addKustomizeResources(dir, []kustomizeResource{
+		{fmt.Sprintf("%s.yml", name), "", o},
+	})
+}
+
+// failHookJob waits for the given hook Job to be created and then emulates the Job controller and kubelet by creating
+// a failed Pod for the Job and marking the Job as failed, as the test cluster does not run any controllers
+func (s *hooksTestContext) failHookJob(name string, output string) {
+	jobsGvr := batchv1.SchemeGroupVersion.WithResource("jobs")
+	podsGvr := corev1.SchemeGroupVersion.WithResource("pods")
+
+	var job *uo.UnstructuredObject
+	for i := 0; i < 60; i++ {
+		x, err := s.k.Get(jobsGvr, s.p.projectName, name)
+		if err == nil {
+			job = x
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if job == nil {
+		s.t.Errorf("hook job %s was not created", name)
+		return
+	}
+
+	pod := createCoreV1Object("Pod", resourceOpts{
+		name:      name + "-pod",
+		namespace: s.p.projectName,
+		labels:    map[string]string{"job-name": name},
+	})
+	_ = pod.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"name":  "job",
+			"image": "busybox",
+		},
+	}, "spec", "containers")
+	_ = pod.SetNestedField("Never", "spec", "restartPolicy")
+	x, err := s.k.DynamicClient.Resource(podsGvr).Namespace(s.p.projectName).
+		Create(context.Background(), pod.ToUnstructured(), metav1.CreateOptions{})
+	if err != nil {
+		s.t.Error(err)
+		return
+	}
+	pod = uo.FromUnstructured(x)
+	_ = pod.SetNestedField("Failed", "status", "phase")
+	_ = pod.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"name":  "job",
+			"image": "busybox",
+			"ready": false,
+			"state": map[string]interface{}{
+				"terminated": map[string]interface{}{
+					"reason":   "Error",
+					"exitCode": int64(1),
+					"message":  output,
+				},
+			},
+		},
+	}, "status", "containerStatuses")
+	_, err = s.k.DynamicClient.Resource(podsGvr).Namespace(s.p.projectName).
+		UpdateStatus(context.Background(), pod.ToUnstructured(), metav1.UpdateOptions{})
+	if err != nil {
+		s.t.Error(err)
+		return
+	}
+
+	_ = job.SetNestedField(int64(1), "status", "failed")
+	_ = job.SetNestedField(metav1.Now().UTC().Format(time.RFC3339), "status", "startTime")
+	_ = job.SetNestedField([]interface{}{
+		map[string]interface{}{
+			"type":    "Failed",
+			"status":  "True",
+			"reason":  "BackoffLimitExceeded",
+			"message": "Job has reached the specified backoff limit",
+		},
+	}, "status", "conditions")
+	_, err = s.k.DynamicClient.Resource(jobsGvr).Namespace(s.p.projectName).
+		UpdateStatus(context.Background(), job.ToUnstructured(), metav1.UpdateOptions{})
+	if err != nil {
+		s.t.Error(err)
+	}
+}
+
+func TestHooksFailedJobLogs(t *testing.T) {
+	t.Parallel()
+	s := prepareHookTestProject(t, "failed-job-logs", "post-deploy", "")
+	s.createDefaultServiceAccount()
+	s.addHookJob("hook", "hook-job", "post-deploy")
+
+	resultFile := filepath.Join(t.TempDir(), "result.yaml")
+	errCh := make(chan error)
+	go func() {
+		_, _, err := s.p.Kluctl("deploy", "--yes", "-t", "test", "--readiness-timeout", "30s", "-o", "yaml="+resultFile)
+		errCh <- err
+	}()
+	s.failHookJob("hook-job", "starting migration\nerror: migration failed\n")
+	err := <-errCh
+	assert.Error(t, err)
+
+	var result types.CommandResult
+	err = yaml.ReadYamlFile(resultFile, &result)
+	assert.NoError(t, err)
+
+	jobRef := k8s.NewObjectRef("batch", "v1", "Job", "hook-job", s.p.projectName)
+	var logs string
+	for _, e := range result.Errors {
+		if e.Ref == jobRef {
+			logs = e.Logs
+		}
+	}
+	assert.Contains(t, logs, "pod/hook-job-pod container job:")
+	assert.Contains(t, logs, "error: migration failed")
+}
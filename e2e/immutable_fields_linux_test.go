@@ -0,0 +1,110 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"testing"
+)
+
+func createJobObject(image string, opts resourceOpts) *uo.UnstructuredObject {
+	o := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: job
+        image: ` + image + `
+`)
+	mergeMetadata(o, opts)
+	return o
+}
+
+func assertJobImage(t *testing.T, p *testProject, name string, image string) string {
+	x := defaultCluster1.MustGet(t, batchv1.SchemeGroupVersion.WithResource("jobs"), p.projectName, name)
+	containers := x.GetNestedObjectListNoErr("spec", "template", "spec", "containers")
+	assert.Len(t, containers, 1)
+	assertNestedFieldEquals(t, containers[0], image, "image")
+	return x.GetK8sUid()
+}
+
+func TestRecreateImmutableChangeConfirmed(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, "recreate-immutable-confirmed")
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	p.addKustomizeDeployment("job", []kustomizeResource{
+		{"job.yml", "", createJobObject("busybox:1", resourceOpts{name: "job", namespace: p.projectName})},
+	}, nil)
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	uid := assertJobImage(t, p, "job", "busybox:1")
+
+	p.updateYaml("job/job.yml", func(o *uo.UnstructuredObject) error {
+		*o = *createJobObject("busybox:2", resourceOpts{name: "job", namespace: p.projectName})
+		return nil
+	}, "")
+
+	// without confirmations, the change to the immutable spec.template can't be applied
+	_, _, err := p.Kluctl("deploy", "--yes", "-t", "test")
+	assert.Error(t, err)
+	assert.Equal(t, uid, assertJobImage(t, p, "job", "busybox:1"))
+
+	// the diff is confirmed but the recreation is declined
+	_, stderr, err := p.KluctlWithAnswers([]string{"y", "n"}, "deploy", "-t", "test")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "The following objects require recreation")
+	assert.Equal(t, uid, assertJobImage(t, p, "job", "busybox:1"))
+
+	// both the diff and the recreation are confirmed
+	_, stderr, err = p.KluctlWithAnswers([]string{"y", "y"}, "deploy", "-t", "test")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "The following objects require recreation")
+	newUid := assertJobImage(t, p, "job", "busybox:2")
+	assert.NotEqual(t, uid, newUid)
+}
+
+func TestRecreateImmutableChangeAnnotation(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, "recreate-immutable-annotation")
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	opts := resourceOpts{
+		name:        "job",
+		namespace:   p.projectName,
+		annotations: map[string]string{"kluctl.io/apply-strategy": "recreate-on-immutable-change"},
+	}
+	p.addKustomizeDeployment("job", []kustomizeResource{
+		{"job.yml", "", createJobObject("busybox:1", opts)},
+	}, nil)
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	uid := assertJobImage(t, p, "job", "busybox:1")
+
+	p.updateYaml("job/job.yml", func(o *uo.UnstructuredObject) error {
+		*o = *createJobObject("busybox:2", opts)
+		return nil
+	}, "")
+
+	// the annotation allows recreation without any confirmation
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	newUid := assertJobImage(t, p, "job", "busybox:2")
+	assert.NotEqual(t, uid, newUid)
+}
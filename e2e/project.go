@@ -323,6 +323,12 @@ func (p *testProject) getKluctlProjectRepo() string {
 }
 
 func (p *testProject) Kluctl(argsIn ...string) (string, string, error) {
+	cmd := p.buildKluctlCmd(argsIn...)
+	stdout, stderr, err := runHelper(p.t, cmd)
+	return stdout, stderr, err
+}
+
+func (p *testProject) buildKluctlCmd(argsIn ...string) *exec.Cmd {
 	var args []string
 	args = append(args, argsIn...)
 	args = append(args, "--no-update-check")
@@ -348,9 +354,7 @@ func (p *testProject) Kluctl(argsIn ...string) (string, string, error) {
 	cmd := exec.Command(testExe, args...)
 	cmd.Dir = cwd
 	cmd.Env = env
-
-	stdout, stderr, err := runHelper(p.t, cmd)
-	return stdout, stderr, err
+	return cmd
 }
 
 func (p *testProject) KluctlMust(argsIn ...string) (string, string) {
@@ -0,0 +1,72 @@
+package e2e
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollbackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, "rollback-on-failure")
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	addConfigMapDeployment(p, "app", map[string]string{"a": "v1"}, resourceOpts{
+		name:      "cm",
+		namespace: p.projectName,
+	})
+	p.updateDeploymentItems("", func(items []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+		_ = items[0].SetNestedField(true, "waitReadiness")
+		_ = items[0].SetNestedField("rollback", "onFailure")
+		return items
+	})
+
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v1", "data", "a")
+
+	// change the ConfigMap and add a Deployment that never gets ready, as no controllers are running in the test cluster
+	p.updateYaml("app/configmap-cm.yml", func(o *uo.UnstructuredObject) error {
+		return o.SetNestedField("v2", "data", "a")
+	}, "")
+	p.addKustomizeResources("app", []kustomizeResource{
+		{"deployment.yml", "", createRolloutDeploymentObject("app:1", resourceOpts{name: "app", namespace: p.projectName})},
+	})
+
+	resultFile := filepath.Join(t.TempDir(), "result.yaml")
+	_, _, err := p.Kluctl("deploy", "--yes", "-t", "test", "--readiness-timeout", "5s", "-o", "yaml="+resultFile)
+	assert.Error(t, err)
+
+	// the changed ConfigMap is restored and the new Deployment is deleted
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.projectName, "cm"), "v1", "data", "a")
+	_, err = k.Get(appsv1.SchemeGroupVersion.WithResource("deployments"), p.projectName, "app")
+	assert.True(t, errors.IsNotFound(err))
+
+	var result types.CommandResult
+	err = yaml.ReadYamlFile(resultFile, &result)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []types.RolledBackObject{
+		{Ref: k8s2.NewObjectRef("", "v1", "ConfigMap", "cm", p.projectName), Action: "restored"},
+		{Ref: k8s2.NewObjectRef("apps", "v1", "Deployment", "app", p.projectName), Action: "deleted"},
+	}, result.RolledBackObjects)
+
+	// rolled back objects must not be reported as changed or new, as the new state is not present in the cluster
+	for _, x := range result.ChangedObjects {
+		assert.NotEqual(t, "cm", x.Ref.Name)
+	}
+	for _, x := range result.NewObjects {
+		assert.NotEqual(t, "app", x.Ref.Name)
+	}
+}
@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"testing"
+)
+
+func createRolloutDeploymentObject(image string, opts resourceOpts) *uo.UnstructuredObject {
+	o := uo.FromStringMust(fmt.Sprintf(`
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 4
+  selector:
+    matchLabels:
+      app: %[1]s
+  template:
+    metadata:
+      labels:
+        app: %[1]s
+    spec:
+      containers:
+      - name: app
+        image: %[2]s
+`, opts.name, image))
+	mergeMetadata(o, opts)
+	return o
+}
+
+func TestRolloutDeploymentRevert(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := &testProject{}
+	p.init(t, k, "rollout-revert")
+
+	createNamespace(t, k, p.projectName)
+
+	p.updateTarget("test", nil)
+
+	opts := resourceOpts{
+		name:        "app",
+		namespace:   p.projectName,
+		annotations: map[string]string{"kluctl.io/rollout-steps": "25,50"},
+	}
+	p.addKustomizeDeployment("app", []kustomizeResource{
+		{"deployment.yml", "", createRolloutDeploymentObject("app:1", opts)},
+	}, nil)
+
+	// initial deployments are not rolled out progressively
+	p.KluctlMust("deploy", "--yes", "-t", "test")
+
+	p.updateYaml("app/deployment.yml", func(o *uo.UnstructuredObject) error {
+		*o = *createRolloutDeploymentObject("app:2", opts)
+		return nil
+	}, "")
+
+	// there are no controllers running in the test cluster, so the canary never gets ready and the rollout must be
+	// reverted in the first step
+	stdout, stderr, err := p.Kluctl("deploy", "--yes", "-t", "test", "--readiness-timeout", "5s")
+	assert.Error(t, err)
+	assert.Contains(t, stdout+stderr, "was aborted in step 1")
+
+	x, err := k.Get(appsv1.SchemeGroupVersion.WithResource("deployments"), p.projectName, "app")
+	assert.NoError(t, err)
+	containers := x.GetNestedObjectListNoErr("spec", "template", "spec", "containers")
+	assert.Len(t, containers, 1)
+	assertNestedFieldEquals(t, containers[0], "app:1", "image")
+	assertNestedFieldEquals(t, x, int64(4), "spec", "replicas")
+
+	_, err = k.Get(appsv1.SchemeGroupVersion.WithResource("deployments"), p.projectName, "app-rollout-canary")
+	assert.Error(t, err)
+}
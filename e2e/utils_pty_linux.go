@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"os"
+	"strings"
+	"sync"
+)
+
+// openPty opens a new pseudo terminal and returns its master and slave ends
+func openPty() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	fd := int(master.Fd())
+	err = unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// KluctlWithAnswers runs kluctl with stderr attached to a pseudo terminal, so that kluctl asks for confirmations.
+// The given answers are passed via stdin, one per line.
+func (p *testProject) KluctlWithAnswers(answers []string, argsIn ...string) (string, string, error) {
+	master, slave, err := openPty()
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	defer master.Close()
+
+	cmd := p.buildKluctlCmd(argsIn...)
+	stdoutBuf := bytes.NewBuffer(nil)
+	stderrBuf := bytes.NewBuffer(nil)
+	cmd.Stdin = strings.NewReader(strings.Join(answers, "\n") + "\n")
+	cmd.Stdout = stdoutBuf
+	cmd.Stderr = slave
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// reading fails with EIO as soon as the slave end gets closed
+		scanner := bufio.NewScanner(master)
+		for scanner.Scan() {
+			l := scanner.Text()
+			p.t.Log("stderr: " + l)
+			stderrBuf.WriteString(l + "\n")
+		}
+	}()
+
+	err = cmd.Run()
+	_ = slave.Close()
+	wg.Wait()
+	return stdoutBuf.String(), stderrBuf.String(), err
+}
@@ -135,7 +135,8 @@ func getHelpSection(command string, section string) []string {
         log.Fatal(err)
     }
 
-    helpCmd := exec.Command(exe, command, "--help")
+    helpArgs := append(strings.Split(command, " "), "--help")
+    helpCmd := exec.Command(exe, helpArgs...)
     helpCmd.Env = os.Environ()
     helpCmd.Env = append(helpCmd.Env, "CALL_KLUCTL=true")
 
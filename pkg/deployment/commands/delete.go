@@ -2,16 +2,32 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"path/filepath"
+	"time"
 )
 
 type DeleteCommand struct {
 	c                      *deployment.DeploymentCollection
 	OverrideDeleteByLabels map[string]string
+
+	deleteOptions
+}
+
+// deleteOptions contains the options shared by the delete and prune commands
+type deleteOptions struct {
+	ReadinessTimeout      time.Duration
+	HookLogs              bool
+	AllowCommandHooks     bool
+	AllowProtected        bool
+	RemoveFinalizersAfter time.Duration
 }
 
 func NewDeleteCommand(c *deployment.DeploymentCollection) *DeleteCommand {
@@ -20,7 +36,7 @@ func NewDeleteCommand(c *deployment.DeploymentCollection) *DeleteCommand {
 	}
 }
 
-func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster) ([]k8s2.ObjectRef, error) {
+func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster, confirmCb func(refs []k8s2.ObjectRef) error) (*types.CommandResult, error) {
 	dew := utils2.NewDeploymentErrorsAndWarnings()
 
 	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
@@ -42,5 +58,108 @@ func (cmd *DeleteCommand) Run(ctx context.Context, k *k8s.K8sCluster) ([]k8s2.Ob
 		return nil, err
 	}
 
-	return utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(inclusion), inclusion.HasType("tags"), nil)
+	refs, err := utils2.FindObjectsForDelete(k, ru.GetFilteredRemoteObjects(inclusion), inclusion.HasType("tags"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteObjectsWithHooks(ctx, k, cmd.c, dew, ru, refs, "delete", cmd.deleteOptions, confirmCb)
+}
+
+// deleteObjectsWithHooks deletes the given objects and runs the pre-<hookPhase> and post-<hookPhase> hooks of all
+// deployment items which own at least one of the deleted objects. hookPhase is either "delete" or "prune". Ownership
+// is determined via the kluctl.io/kustomize_dir annotation of the remote objects.
+func deleteObjectsWithHooks(ctx context.Context, k *k8s.K8sCluster, c *deployment.DeploymentCollection, dew *utils2.DeploymentErrorsAndWarnings, ru *utils2.RemoteObjectUtils, refs []k8s2.ObjectRef, hookPhase string, do deleteOptions, confirmCb func(refs []k8s2.ObjectRef) error) (*types.CommandResult, error) {
+	var protectedRefs []k8s2.ObjectRef
+	if !do.AllowProtected {
+		protectedKinds := deployment.GetDefaultProtectedKinds()
+		if c != nil {
+			protectedKinds = c.Project.GetProtectedKinds()
+		}
+		var err error
+		refs, protectedRefs, err = utils2.FilterProtectedObjects(k, ru, refs, protectedKinds)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range protectedRefs {
+			dew.AddWarning(ref, fmt.Errorf("object is protected from deletion, use --allow-protected to delete it"))
+		}
+	}
+
+	if confirmCb != nil {
+		err := confirmCb(refs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ownerDirs := make(map[string]bool)
+	for _, ref := range refs {
+		o := ru.GetRemoteObject(ref)
+		if o == nil {
+			continue
+		}
+		if itemDir := o.GetK8sAnnotation("kluctl.io/kustomize_dir"); itemDir != nil {
+			ownerDirs[*itemDir] = true
+		}
+	}
+
+	var owners []*deployment.DeploymentItem
+	if c != nil {
+		for _, d := range c.Deployments {
+			if _, ok := ownerDirs[filepath.ToSlash(d.RelToSourceItemDir)]; ok && d.RelToSourceItemDir != "" {
+				owners = append(owners, d)
+			}
+		}
+	}
+
+	var readinessRules *validation.ReadinessRules
+	if c != nil {
+		var err error
+		readinessRules, err = validation.NewReadinessRules(c.Project.GetReadinessRules())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	o := &utils2.ApplyUtilOptions{
+		DryRun:            k.DryRun,
+		ReadinessTimeout:  do.ReadinessTimeout,
+		HookLogs:          do.HookLogs,
+		AllowCommandHooks: do.AllowCommandHooks,
+		ReadinessRules:    readinessRules,
+	}
+	ad := utils2.NewApplyDeploymentsUtil(ctx, dew, owners, ru, k, o)
+
+	runHooks := func(hooks []string) {
+		for _, d := range owners {
+			au := ad.NewApplyUtil(ctx, nil)
+			h := utils2.NewHooksUtil(au)
+			h.RunHooks(h.DetermineHooks(d, hooks))
+		}
+	}
+
+	runHooks([]string{"pre-" + hookPhase})
+
+	result := &types.CommandResult{}
+	if len(dew.GetErrorsList()) == 0 {
+		// only delete if the pre-delete/pre-prune hooks succeeded, same as Helm does
+		var deleteOrder *types.DeleteOrderConfig
+		if c != nil {
+			deleteOrder = c.Project.GetDeleteOrder()
+		}
+		r, err := utils2.DeleteObjects(k, utils2.CalcDeletePhases(k, refs, deleteOrder), true, do.RemoveFinalizersAfter)
+		if err != nil {
+			return nil, err
+		}
+		result = r
+
+		runHooks([]string{"post-" + hookPhase})
+	}
+
+	result.ProtectedObjects = protectedRefs
+	result.HookObjects = ad.GetAppliedHookObjects()
+	result.Errors = append(result.Errors, dew.GetErrorsList()...)
+	result.Warnings = append(result.Warnings, dew.GetWarningsList()...)
+	return result, nil
 }
@@ -6,6 +6,8 @@ import (
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
 	"time"
 )
 
@@ -17,7 +19,11 @@ type DeployCommand struct {
 	ForceReplaceOnError bool
 	AbortOnError        bool
 	ReadinessTimeout    time.Duration
+	HookLogs            bool
+	AllowCommandHooks   bool
 	NoWait              bool
+
+	ImageVerificationKeys []types.ImageVerificationKey
 }
 
 func NewDeployCommand(c *deployment.DeploymentCollection) *DeployCommand {
@@ -29,8 +35,26 @@ func NewDeployCommand(c *deployment.DeploymentCollection) *DeployCommand {
 func (cmd *DeployCommand) Run(ctx context.Context, k *k8s.K8sCluster, diffResultCb func(diffResult *types.CommandResult) error) (*types.CommandResult, error) {
 	dew := utils2.NewDeploymentErrorsAndWarnings()
 
+	err := utils2.VerifyImageSignatures(ctx, dew, cmd.c.Images, cmd.ImageVerificationKeys)
+	if err != nil {
+		return nil, err
+	}
+	if len(dew.GetErrorsList()) != 0 {
+		// don't deploy anything if any image failed verification
+		return &types.CommandResult{
+			Errors:     dew.GetErrorsList(),
+			Warnings:   dew.GetWarningsList(),
+			SeenImages: cmd.c.Images.SeenImages(false),
+		}, nil
+	}
+
 	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
-	err := ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), cmd.c.LocalObjectRefs())
+	err = ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), cmd.c.LocalObjectRefs())
+	if err != nil {
+		return nil, err
+	}
+
+	readinessRules, err := validation.NewReadinessRules(cmd.c.Project.GetReadinessRules())
 	if err != nil {
 		return nil, err
 	}
@@ -43,14 +67,18 @@ func (cmd *DeployCommand) Run(ctx context.Context, k *k8s.K8sCluster, diffResult
 		DryRun:              true,
 		AbortOnError:        false,
 		ReadinessTimeout:    cmd.ReadinessTimeout,
+		HookLogs:            cmd.HookLogs,
+		AllowCommandHooks:   cmd.AllowCommandHooks,
 		NoWait:              cmd.NoWait,
+		ReadinessRules:      readinessRules,
 	}
 
 	if diffResultCb != nil {
 		au := utils2.NewApplyDeploymentsUtil(ctx, dew, cmd.c.Deployments, ru, k, o)
 		au.ApplyDeployments()
 
-		du := utils2.NewDiffUtil(dew, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+		du := utils2.NewDiffUtil(dew, k, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+		du.RequireRecreation = au.GetRequireRecreation()
 		du.Diff()
 
 		orphanObjects, err := FindOrphanObjects(k, ru, cmd.c)
@@ -69,6 +97,14 @@ func (cmd *DeployCommand) Run(ctx context.Context, k *k8s.K8sCluster, diffResult
 		if err != nil {
 			return nil, err
 		}
+
+		// the callback did not abort, so re-creation of these objects is confirmed
+		o.RecreateObjects = map[k8s2.ObjectRef]bool{}
+		for _, co := range diffResult.ChangedObjects {
+			if co.RequiresRecreation {
+				o.RecreateObjects[co.Ref] = true
+			}
+		}
 	}
 
 	// clear errors and warnings
@@ -81,21 +117,30 @@ func (cmd *DeployCommand) Run(ctx context.Context, k *k8s.K8sCluster, diffResult
 	au := utils2.NewApplyDeploymentsUtil(ctx, dew, cmd.c.Deployments, ru, k, o)
 	au.ApplyDeployments()
 
-	du := utils2.NewDiffUtil(dew, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+	du := utils2.NewDiffUtil(dew, k, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+	du.RequireRecreation = au.GetRequireRecreation()
 	du.Diff()
 
 	orphanObjects, err := FindOrphanObjects(k, ru, cmd.c)
 	if err != nil {
 		return nil, err
 	}
+
+	var health []types.DeploymentItemHealth
+	if !o.DryRun {
+		health = au.GetHealth()
+	}
+
 	return &types.CommandResult{
-		NewObjects:     du.NewObjects,
-		ChangedObjects: du.ChangedObjects,
-		DeletedObjects: au.GetDeletedObjects(),
-		HookObjects:    au.GetAppliedHookObjects(),
-		OrphanObjects:  orphanObjects,
-		Errors:         dew.GetErrorsList(),
-		Warnings:       dew.GetWarningsList(),
-		SeenImages:     cmd.c.Images.SeenImages(false),
+		NewObjects:        du.NewObjects,
+		ChangedObjects:    du.ChangedObjects,
+		DeletedObjects:    au.GetDeletedObjects(),
+		HookObjects:       au.GetAppliedHookObjects(),
+		OrphanObjects:     orphanObjects,
+		RolledBackObjects: au.GetRolledBackObjects(),
+		Errors:            dew.GetErrorsList(),
+		Warnings:          dew.GetWarningsList(),
+		SeenImages:        cmd.c.Images.SeenImages(false),
+		Health:            health,
 	}, nil
 }
@@ -17,6 +17,7 @@ type DiffCommand struct {
 	IgnoreTags          bool
 	IgnoreLabels        bool
 	IgnoreAnnotations   bool
+	ShowOwnership       bool
 }
 
 func NewDiffCommand(c *deployment.DeploymentCollection) *DiffCommand {
@@ -45,10 +46,12 @@ func (cmd *DiffCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.Comm
 	au := utils.NewApplyDeploymentsUtil(ctx, dew, cmd.c.Deployments, ru, k, o)
 	au.ApplyDeployments()
 
-	du := utils.NewDiffUtil(dew, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+	du := utils.NewDiffUtil(dew, k, cmd.c.Deployments, ru, au.GetAppliedObjectsMap())
+	du.RequireRecreation = au.GetRequireRecreation()
 	du.IgnoreTags = cmd.IgnoreTags
 	du.IgnoreLabels = cmd.IgnoreLabels
 	du.IgnoreAnnotations = cmd.IgnoreAnnotations
+	du.ShowOwnership = cmd.ShowOwnership
 	du.Diff()
 
 	orphanObjects, err := FindOrphanObjects(k, ru, cmd.c)
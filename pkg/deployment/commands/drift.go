@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+)
+
+type DriftCommand struct {
+	c *deployment.DeploymentCollection
+
+	IgnoreTags        bool
+	IgnoreLabels      bool
+	IgnoreAnnotations bool
+}
+
+func NewDriftCommand(c *deployment.DeploymentCollection) *DriftCommand {
+	return &DriftCommand{
+		c: c,
+	}
+}
+
+// Run performs a dry-run diff of the target and reports all objects that differ from the rendered target. Nothing is
+// modified in the cluster.
+func (cmd *DriftCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.DriftResult, error) {
+	diffCmd := NewDiffCommand(cmd.c)
+	diffCmd.IgnoreTags = cmd.IgnoreTags
+	diffCmd.IgnoreLabels = cmd.IgnoreLabels
+	diffCmd.IgnoreAnnotations = cmd.IgnoreAnnotations
+
+	cr, err := diffCmd.Run(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	var missingObjects []k8s2.ObjectRef
+	for _, o := range cr.NewObjects {
+		missingObjects = append(missingObjects, o.Ref)
+	}
+
+	// the full objects are not part of the report, only the actual changes
+	var changedObjects []*types.ChangedObject
+	for _, co := range cr.ChangedObjects {
+		changedObjects = append(changedObjects, &types.ChangedObject{
+			Ref:     co.Ref,
+			Changes: co.Changes,
+		})
+	}
+
+	result := &types.DriftResult{
+		MissingObjects: missingObjects,
+		ChangedObjects: changedObjects,
+		DeletedObjects: cr.DeletedObjects,
+		OrphanObjects:  cr.OrphanObjects,
+		Errors:         cr.Errors,
+		Warnings:       cr.Warnings,
+	}
+	result.Drifted = len(result.MissingObjects) != 0 || len(result.ChangedObjects) != 0 ||
+		len(result.DeletedObjects) != 0 || len(result.OrphanObjects) != 0
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"time"
+)
+
+type HelmTestCommand struct {
+	c *deployment.DeploymentCollection
+
+	ReadinessTimeout  time.Duration
+	HookLogs          bool
+	AllowCommandHooks bool
+}
+
+func NewHelmTestCommand(c *deployment.DeploymentCollection) *HelmTestCommand {
+	return &HelmTestCommand{
+		c: c,
+	}
+}
+
+func (cmd *HelmTestCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.CommandResult, error) {
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+	err := ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), cmd.c.LocalObjectRefs())
+	if err != nil {
+		return nil, err
+	}
+
+	readinessRules, err := validation.NewReadinessRules(cmd.c.Project.GetReadinessRules())
+	if err != nil {
+		return nil, err
+	}
+
+	o := &utils2.ApplyUtilOptions{
+		DryRun:            k.DryRun,
+		ReadinessTimeout:  cmd.ReadinessTimeout,
+		HookLogs:          cmd.HookLogs,
+		AllowCommandHooks: cmd.AllowCommandHooks,
+		ReadinessRules:    readinessRules,
+	}
+	ad := utils2.NewApplyDeploymentsUtil(ctx, dew, cmd.c.Deployments, ru, k, o)
+
+	for _, d := range cmd.c.Deployments {
+		if !d.CheckInclusionForDeploy() {
+			continue
+		}
+
+		au := ad.NewApplyUtil(ctx, nil)
+		h := utils2.NewHooksUtil(au)
+		hooks := h.DetermineHooks(d, []string{"test"})
+		if len(hooks) == 0 {
+			continue
+		}
+
+		s := status.StartWithOptions(ctx,
+			status.WithTotal(len(hooks)),
+			status.WithPrefix(d.RelToProjectItemDir),
+			status.WithStatus("Running %d tests", len(hooks)),
+		)
+		h.RunHooks(hooks)
+
+		failed := 0
+		for _, x := range hooks {
+			if au.HadError(x.Ref()) {
+				failed++
+			}
+		}
+		if failed == 0 {
+			s.UpdateAndInfoFallback("All %d tests succeeded", len(hooks))
+			s.Success()
+		} else {
+			s.FailedWithMessage("%d of %d tests failed", failed, len(hooks))
+		}
+	}
+
+	return &types.CommandResult{
+		HookObjects: ad.GetAppliedHookObjects(),
+		Errors:      dew.GetErrorsList(),
+		Warnings:    dew.GetWarningsList(),
+	}, nil
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"sort"
+)
+
+type OwnershipCommand struct {
+	c *deployment.DeploymentCollection
+
+	OnlyConflicts bool
+}
+
+func NewOwnershipCommand(c *deployment.DeploymentCollection) *OwnershipCommand {
+	return &OwnershipCommand{
+		c: c,
+	}
+}
+
+func (cmd *OwnershipCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.OwnershipResult, error) {
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+	err := ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), cmd.c.LocalObjectRefs())
+	if err != nil {
+		return nil, err
+	}
+
+	var result types.OwnershipResult
+	for _, d := range cmd.c.Deployments {
+		if !d.CheckInclusionForDeploy() {
+			continue
+		}
+		for _, o := range d.Objects {
+			ref := o.GetK8sRef()
+			remoteObject := ru.GetRemoteObject(ref)
+			if remoteObject == nil {
+				continue
+			}
+
+			fields, err := diff.GetFieldOwnership(o, remoteObject)
+			if err != nil {
+				dew.AddError(ref, err)
+				continue
+			}
+			if cmd.OnlyConflicts {
+				var filtered []types.FieldOwnership
+				for _, f := range fields {
+					if f.Conflict {
+						filtered = append(filtered, f)
+					}
+				}
+				fields = filtered
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			result.Objects = append(result.Objects, types.ObjectOwnership{
+				Ref:    ref,
+				Fields: fields,
+			})
+		}
+	}
+	sort.Slice(result.Objects, func(i, j int) bool {
+		return result.Objects[i].Ref.String() < result.Objects[j].Ref.String()
+	})
+
+	result.Errors = dew.GetErrorsList()
+	result.Warnings = dew.GetWarningsList()
+	return &result, nil
+}
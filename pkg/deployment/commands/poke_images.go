@@ -61,7 +61,11 @@ func (cmd *PokeImagesCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*type
 			for _, c := range containers {
 				containerName, _, _ := c.GetNestedString("name")
 				if image.Container != nil && containerName == *image.Container {
-					c.SetNestedField(image.ResultImage, "image")
+					resultImage := image.ResultImage
+					if image.RewrittenImage != nil {
+						resultImage = *image.RewrittenImage
+					}
+					c.SetNestedField(resultImage, "image")
 				}
 			}
 		}
@@ -89,7 +93,7 @@ func (cmd *PokeImagesCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*type
 	}
 	wg.Wait()
 
-	du := utils2.NewDiffUtil(dew, cmd.c.Deployments, ru, ad.GetAppliedObjectsMap())
+	du := utils2.NewDiffUtil(dew, k, cmd.c.Deployments, ru, ad.GetAppliedObjectsMap())
 	du.Diff()
 
 	return &types.CommandResult{
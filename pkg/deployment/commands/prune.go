@@ -5,11 +5,14 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 )
 
 type PruneCommand struct {
 	c *deployment.DeploymentCollection
+
+	deleteOptions
 }
 
 func NewPruneCommand(c *deployment.DeploymentCollection) *PruneCommand {
@@ -18,7 +21,7 @@ func NewPruneCommand(c *deployment.DeploymentCollection) *PruneCommand {
 	}
 }
 
-func (cmd *PruneCommand) Run(ctx context.Context, k *k8s.K8sCluster) ([]k8s2.ObjectRef, error) {
+func (cmd *PruneCommand) Run(ctx context.Context, k *k8s.K8sCluster, confirmCb func(refs []k8s2.ObjectRef) error) (*types.CommandResult, error) {
 	dew := utils2.NewDeploymentErrorsAndWarnings()
 
 	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
@@ -27,7 +30,12 @@ func (cmd *PruneCommand) Run(ctx context.Context, k *k8s.K8sCluster) ([]k8s2.Obj
 		return nil, err
 	}
 
-	return FindOrphanObjects(k, ru, cmd.c)
+	refs, err := FindOrphanObjects(k, ru, cmd.c)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteObjectsWithHooks(ctx, k, cmd.c, dew, ru, refs, "prune", cmd.deleteOptions, confirmCb)
 }
 
 func FindOrphanObjects(k *k8s.K8sCluster, ru *utils2.RemoteObjectUtils, c *deployment.DeploymentCollection) ([]k8s2.ObjectRef, error) {
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// StatusCommand summarizes the live state of a target. It does not rely on rendered objects, so that it works without
+// rendering the deployment items (and thus without the secrets possibly required for rendering). Objects are instead
+// found via the commonLabels and assigned to deployment items via the kluctl.io/kustomize_dir annotation.
+type StatusCommand struct {
+	c *deployment.DeploymentCollection
+}
+
+func NewStatusCommand(c *deployment.DeploymentCollection) *StatusCommand {
+	return &StatusCommand{
+		c: c,
+	}
+}
+
+func (cmd *StatusCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.StatusResult, error) {
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	ru := utils2.NewRemoteObjectsUtil(ctx, dew)
+	err := ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	readinessRules, err := validation.NewReadinessRules(cmd.c.Project.GetReadinessRules())
+	if err != nil {
+		return nil, err
+	}
+
+	remoteObjects := ru.GetFilteredRemoteObjects(cmd.c.Inclusion)
+
+	objectsByDir := map[string][]*uo.UnstructuredObject{}
+	for _, o := range remoteObjects {
+		// objects owned by other objects (e.g. Pods of a Deployment) are covered by their owners
+		if len(o.GetK8sOwnerReferences()) != 0 || utils2.IsHookObject(o) {
+			continue
+		}
+		dir := o.GetK8sAnnotation("kluctl.io/kustomize_dir")
+		if dir == nil {
+			continue
+		}
+		objectsByDir[*dir] = append(objectsByDir[*dir], o)
+	}
+
+	s := status.Start(ctx, "Collecting status of deployment items")
+	defer s.Failed()
+
+	var result types.StatusResult
+	knownDirs := map[string]bool{}
+	for _, d := range cmd.c.Deployments {
+		if d.Config.Path == nil {
+			continue
+		}
+		dir := filepath.ToSlash(d.RelToSourceItemDir)
+		if knownDirs[dir] {
+			continue
+		}
+		knownDirs[dir] = true
+
+		if d.Config.OnlyRender || !d.CheckInclusionForDeploy() {
+			continue
+		}
+
+		result.Items = append(result.Items, cmd.buildItemStatus(k, dew, readinessRules, d, objectsByDir[dir]))
+	}
+
+	orphanRefs, err := utils2.FindObjectsForDelete(k, remoteObjects, cmd.c.Inclusion.HasType("tags"), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range orphanRefs {
+		o := ru.GetRemoteObject(ref)
+		if o == nil {
+			continue
+		}
+		dir := o.GetK8sAnnotation("kluctl.io/kustomize_dir")
+		if dir != nil && knownDirs[*dir] {
+			continue
+		}
+		result.OrphanObjects = append(result.OrphanObjects, ref)
+	}
+	sort.Slice(result.OrphanObjects, func(i, j int) bool {
+		return result.OrphanObjects[i].String() < result.OrphanObjects[j].String()
+	})
+
+	s.Success()
+
+	result.Errors = dew.GetErrorsList()
+	result.Warnings = dew.GetWarningsList()
+	return &result, nil
+}
+
+func (cmd *StatusCommand) buildItemStatus(k *k8s.K8sCluster, dew *utils2.DeploymentErrorsAndWarnings, readinessRules *validation.ReadinessRules, d *deployment.DeploymentItem, objects []*uo.UnstructuredObject) types.DeploymentItemStatus {
+	ret := types.DeploymentItemStatus{
+		Dir: d.RelToProjectItemDir,
+	}
+	if len(objects) == 0 {
+		// nothing was deployed (yet)
+		ret.Health = types.HealthMissing
+		return ret
+	}
+
+	var healthList []types.ObjectHealth
+	itemImages := map[string]bool{}
+	var itemLastApplied *time.Time
+	for _, o := range objects {
+		ref := o.GetK8sRef()
+		h := validation.GetObjectHealth(k, ref, o, readinessRules)
+		healthList = append(healthList, h)
+
+		objectStatus := types.ObjectStatus{
+			Ref:     ref,
+			Health:  h.Health,
+			Message: h.Message,
+		}
+
+		images, err := utils2.GetRunningImages(k, o)
+		if err != nil {
+			dew.AddWarning(ref, err)
+		}
+		objectStatus.Images = images
+		for _, image := range images {
+			itemImages[image] = true
+		}
+
+		lastApplied := utils2.GetLastApplied(o)
+		if lastApplied != nil {
+			objectStatus.LastApplied = lastApplied.Format(time.RFC3339)
+			if itemLastApplied == nil || lastApplied.After(*itemLastApplied) {
+				itemLastApplied = lastApplied
+			}
+		}
+
+		ret.Objects = append(ret.Objects, objectStatus)
+	}
+	sort.Slice(ret.Objects, func(i, j int) bool {
+		return ret.Objects[i].Ref.String() < ret.Objects[j].Ref.String()
+	})
+
+	ret.Health = validation.AggregateHealth(healthList)
+	for image := range itemImages {
+		ret.Images = append(ret.Images, image)
+	}
+	sort.Strings(ret.Images)
+	if itemLastApplied != nil {
+		ret.LastApplied = itemLastApplied.Format(time.RFC3339)
+	}
+	return ret
+}
@@ -2,18 +2,23 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"strings"
 )
 
 type ValidateCommand struct {
 	c   *deployment.DeploymentCollection
 	dew *utils2.DeploymentErrorsAndWarnings
 	ru  *utils2.RemoteObjectUtils
+
+	watchState *validateWatchState
 }
 
 func NewValidateCommand(ctx context.Context, c *deployment.DeploymentCollection) *ValidateCommand {
@@ -26,9 +31,6 @@ func NewValidateCommand(ctx context.Context, c *deployment.DeploymentCollection)
 }
 
 func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.ValidateResult, error) {
-	var result types.ValidateResult
-	result.Ready = true
-
 	cmd.dew.Init()
 
 	err := cmd.ru.UpdateRemoteObjects(k, cmd.c.Project.GetCommonLabels(), cmd.c.LocalObjectRefs())
@@ -36,40 +38,90 @@ func (cmd *ValidateCommand) Run(ctx context.Context, k *k8s.K8sCluster) (*types.
 		return nil, err
 	}
 
-	ad := utils2.NewApplyDeploymentsUtil(ctx, cmd.dew, cmd.c.Deployments, cmd.ru, k, &utils2.ApplyUtilOptions{})
+	readinessRules, err := validation.NewReadinessRules(cmd.c.Project.GetReadinessRules())
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.validate(ctx, k, cmd.dew, readinessRules, cmd.ru.GetRemoteObject, true), nil
+}
+
+type validatedItem struct {
+	d       *deployment.DeploymentItem
+	objects []*uo.UnstructuredObject
+}
+
+// getValidatedItems returns all included deployment items together with the objects that need validation. Hooks are
+// only validated if these are persistent and part of the deployment.
+func (cmd *ValidateCommand) getValidatedItems(ctx context.Context, k *k8s.K8sCluster, dew *utils2.DeploymentErrorsAndWarnings) []validatedItem {
+	var ret []validatedItem
+
+	ad := utils2.NewApplyDeploymentsUtil(ctx, dew, cmd.c.Deployments, cmd.ru, k, &utils2.ApplyUtilOptions{})
 	for _, d := range cmd.c.Deployments {
 		if !d.CheckInclusionForDeploy() {
 			continue
 		}
 		au := ad.NewApplyUtil(ctx, nil)
 		h := utils2.NewHooksUtil(au)
+
+		vi := validatedItem{d: d}
 		for _, o := range d.Objects {
 			hook := h.GetHook(o)
-			if hook != nil && !hook.IsPersistent() {
+			if hook != nil && (!hook.IsPersistent() || !hook.IsDeployHook()) {
 				continue
 			}
+			vi.objects = append(vi.objects, o)
+		}
+		ret = append(ret, vi)
+	}
+	return ret
+}
 
+// validate validates all objects returned by getObject. If withEvents is true, the recent events of each object that
+// failed validation are attached to its errors.
+func (cmd *ValidateCommand) validate(ctx context.Context, k *k8s.K8sCluster, dew *utils2.DeploymentErrorsAndWarnings, readinessRules *validation.ReadinessRules, getObject func(ref k8s2.ObjectRef) *uo.UnstructuredObject, withEvents bool) *types.ValidateResult {
+	var result types.ValidateResult
+	result.Ready = true
+
+	for _, vi := range cmd.getValidatedItems(ctx, k, dew) {
+		for _, o := range vi.objects {
 			ref := o.GetK8sRef()
 
-			remoteObject := cmd.ru.GetRemoteObject(ref)
+			remoteObject := getObject(ref)
 			if remoteObject == nil {
 				result.Errors = append(result.Errors, types.DeploymentError{Ref: ref, Error: "object not found"})
 				continue
 			}
-			r := validation.ValidateObject(k, remoteObject, true, false)
+			r := validation.ValidateObject(k, remoteObject, true, false, readinessRules)
 			if !r.Ready {
 				result.Ready = false
 			}
+			if withEvents && len(r.Errors) != 0 {
+				events, err := utils2.CollectObjectEvents(k, ref)
+				if err != nil {
+					dew.AddWarning(ref, fmt.Errorf("failed to collect events: %w", err))
+				}
+				for i := range r.Errors {
+					r.Errors[i].Events = strings.Join(events, "\n")
+				}
+			}
 			result.Errors = append(result.Errors, r.Errors...)
 			result.Warnings = append(result.Warnings, r.Warnings...)
 			result.Results = append(result.Results, r.Results...)
 		}
+
+		itemHealth := utils2.CalcDeploymentItemHealth(vi.d, getObject, func(ref k8s2.ObjectRef, o *uo.UnstructuredObject) types.ObjectHealth {
+			return validation.GetObjectHealth(k, ref, o, readinessRules)
+		})
+		if itemHealth != nil {
+			result.Health = append(result.Health, *itemHealth)
+		}
 	}
 
-	result.Warnings = append(result.Warnings, cmd.dew.GetWarningsList()...)
-	result.Errors = append(result.Errors, cmd.dew.GetErrorsList()...)
+	result.Warnings = append(result.Warnings, dew.GetWarningsList()...)
+	result.Errors = append(result.Errors, dew.GetErrorsList()...)
 
-	return &result, nil
+	return &result
 }
 
 func (cmd *ValidateCommand) ForgetRemoteObject(ref k8s2.ObjectRef) {
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"sync"
+	"time"
+)
+
+var (
+	watchResyncInterval = 30 * time.Second
+	// watchRetryInterval is the minimum time between two attempts to start a watch
+	watchRetryInterval = 5 * time.Second
+)
+
+type watchKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+type watchedObject struct {
+	ref k8s2.ObjectRef
+	// o is nil if the object was deleted
+	o *uo.UnstructuredObject
+}
+
+type validateWatchState struct {
+	mutex   sync.Mutex
+	objects map[k8s2.ObjectRef]*uo.UnstructuredObject
+	health  map[k8s2.ObjectRef]types.ObjectHealth
+	result  *types.ValidateResult
+}
+
+// Watch performs an initial validation and then continuously re-validates all objects whenever they change, using the
+// watch API of Kubernetes. initialCb is called with the result of the initial validation, transitionCb is called
+// whenever the health of an object changes. Watch returns when the context is cancelled.
+func (cmd *ValidateCommand) Watch(ctx context.Context, k *k8s.K8sCluster, initialCb func(result *types.ValidateResult) error, transitionCb func(t types.ValidateTransition)) error {
+	result, err := cmd.Run(ctx, k)
+	if err != nil {
+		return err
+	}
+
+	readinessRules, err := validation.NewReadinessRules(cmd.c.Project.GetReadinessRules())
+	if err != nil {
+		return err
+	}
+
+	cmd.watchState = &validateWatchState{
+		objects: map[k8s2.ObjectRef]*uo.UnstructuredObject{},
+		health:  map[k8s2.ObjectRef]types.ObjectHealth{},
+		result:  result,
+	}
+	ws := cmd.watchState
+
+	watchKeys := map[watchKey]bool{}
+	for _, vi := range cmd.getValidatedItems(ctx, k, utils2.NewDeploymentErrorsAndWarnings()) {
+		for _, o := range vi.objects {
+			ref := o.GetK8sRef()
+			remoteObject := cmd.ru.GetRemoteObject(ref)
+			ws.objects[ref] = remoteObject
+			ws.health[ref] = validation.GetObjectHealth(k, ref, remoteObject, readinessRules)
+			watchKeys[watchKey{gvk: ref.GVK, namespace: ref.Namespace}] = true
+		}
+	}
+
+	err = initialCb(result)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan watchedObject)
+	for wk := range watchKeys {
+		go cmd.runWatch(ctx, k, wk, ch)
+	}
+
+	resyncTicker := time.NewTicker(watchResyncInterval)
+	defer resyncTicker.Stop()
+
+	updateHealth := func(ref k8s2.ObjectRef) {
+		ws.mutex.Lock()
+		oldHealth := ws.health[ref]
+		newHealth := validation.GetObjectHealth(k, ref, ws.objects[ref], readinessRules)
+		ws.health[ref] = newHealth
+		ws.mutex.Unlock()
+
+		t := buildValidateTransition(ref, oldHealth, newHealth)
+		if t == nil {
+			return
+		}
+		if t.NewHealth != types.HealthHealthy && t.NewHealth != types.HealthMissing {
+			t.Events = cmd.getWarningEvents(ctx, k, ref)
+		}
+		transitionCb(*t)
+	}
+
+	updateResult := func() {
+		// events are only collected for transitions, as collecting these for each update would be too expensive
+		r := cmd.validate(ctx, k, utils2.NewDeploymentErrorsAndWarnings(), readinessRules, func(ref k8s2.ObjectRef) *uo.UnstructuredObject {
+			ws.mutex.Lock()
+			defer ws.mutex.Unlock()
+			return ws.objects[ref]
+		}, false)
+		ws.mutex.Lock()
+		ws.result = r
+		ws.mutex.Unlock()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case wo := <-ch:
+			ws.mutex.Lock()
+			_, ok := ws.objects[wo.ref]
+			if ok {
+				ws.objects[wo.ref] = wo.o
+			}
+			ws.mutex.Unlock()
+			if !ok {
+				// not part of the deployment, e.g. an orphan object with matching labels
+				continue
+			}
+			updateHealth(wo.ref)
+			updateResult()
+		case <-resyncTicker.C:
+			// some validations depend on time, so we re-evaluate all objects from time to time
+			ws.mutex.Lock()
+			var refs []k8s2.ObjectRef
+			for ref := range ws.objects {
+				refs = append(refs, ref)
+			}
+			ws.mutex.Unlock()
+			for _, ref := range refs {
+				updateHealth(ref)
+			}
+			updateResult()
+		}
+	}
+}
+
+// buildValidateTransition returns the transition from oldHealth to newHealth, or nil if the health did not change
+func buildValidateTransition(ref k8s2.ObjectRef, oldHealth types.ObjectHealth, newHealth types.ObjectHealth) *types.ValidateTransition {
+	if oldHealth.Health == newHealth.Health && oldHealth.Message == newHealth.Message {
+		return nil
+	}
+	return &types.ValidateTransition{
+		Ref:       ref,
+		OldHealth: oldHealth.Health,
+		NewHealth: newHealth.Health,
+		Message:   newHealth.Message,
+	}
+}
+
+// GetWatchResult returns the current result while Watch is running, or nil if Watch was not started yet
+func (cmd *ValidateCommand) GetWatchResult() *types.ValidateResult {
+	ws := cmd.watchState
+	if ws == nil {
+		return nil
+	}
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	return ws.result
+}
+
+// runWatch watches all objects of the given GVK and namespace and restarts the watch whenever it gets closed by the
+// API server
+func (cmd *ValidateCommand) runWatch(ctx context.Context, k *k8s.K8sCluster, wk watchKey, ch chan<- watchedObject) {
+	watchLoop(ctx, wk.gvk.String(), func() (watch.Interface, error) {
+		w, _, err := k.WatchObjects(ctx, wk.gvk, wk.namespace, cmd.c.Project.GetCommonLabels())
+		return w, err
+	}, ch)
+}
+
+// watchLoop forwards all objects received from the watch returned by startWatch to ch and restarts the watch until the
+// context is cancelled. Restarts are delayed by watchRetryInterval if the previous watch failed or was closed
+// immediately, so that a misbehaving API server does not cause a hot loop.
+func watchLoop(ctx context.Context, name string, startWatch func() (watch.Interface, error), ch chan<- watchedObject) {
+	for ctx.Err() == nil {
+		startTime := time.Now()
+		w, err := startWatch()
+		if err != nil {
+			status.Warning(ctx, "Failed to watch %s: %s", name, err.Error())
+		} else {
+			for ev := range w.ResultChan() {
+				u, ok := ev.Object.(*unstructured.Unstructured)
+				if !ok {
+					// most likely an error event, which causes the watch to be restarted
+					continue
+				}
+				o := uo.FromUnstructured(u)
+				wo := watchedObject{ref: o.GetK8sRef()}
+				if ev.Type != watch.Deleted {
+					wo.o = o
+				}
+				select {
+				case ch <- wo:
+				case <-ctx.Done():
+				}
+			}
+			w.Stop()
+		}
+
+		wait := watchRetryInterval - time.Now().Sub(startTime)
+		if wait <= 0 {
+			continue
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// getWarningEvents returns the most recent warning events of the given object and its owned ReplicaSets and Pods
+func (cmd *ValidateCommand) getWarningEvents(ctx context.Context, k *k8s.K8sCluster, ref k8s2.ObjectRef) []string {
+	events, err := utils2.CollectObjectEvents(k, ref)
+	if err != nil {
+		status.Warning(ctx, "Failed to collect events for %s: %s", ref.String(), err.Error())
+		return nil
+	}
+	return events
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/watch"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBuildValidateTransition(t *testing.T) {
+	ref := k8s2.NewObjectRef("apps", "v1", "Deployment", "d1", "default")
+
+	healthy := types.ObjectHealth{Ref: ref, Health: types.HealthHealthy}
+	progressing := types.ObjectHealth{Ref: ref, Health: types.HealthProgressing, Message: "1 of 2 replicas ready"}
+	progressing2 := types.ObjectHealth{Ref: ref, Health: types.HealthProgressing, Message: "2 of 3 replicas ready"}
+
+	assert.Nil(t, buildValidateTransition(ref, healthy, healthy))
+	assert.Nil(t, buildValidateTransition(ref, progressing, progressing))
+
+	assert.Equal(t, &types.ValidateTransition{
+		Ref:       ref,
+		OldHealth: types.HealthHealthy,
+		NewHealth: types.HealthProgressing,
+		Message:   "1 of 2 replicas ready",
+	}, buildValidateTransition(ref, healthy, progressing))
+
+	// a changed message is also a transition
+	tr := buildValidateTransition(ref, progressing, progressing2)
+	assert.NotNil(t, tr)
+	assert.Equal(t, "2 of 3 replicas ready", tr.Message)
+
+	// objects which were not known before have no old health
+	tr = buildValidateTransition(ref, types.ObjectHealth{}, healthy)
+	assert.NotNil(t, tr)
+	assert.Equal(t, "", tr.OldHealth)
+}
+
+// setWatchRetryInterval temporarily overrides the retry interval to speed up tests
+func setWatchRetryInterval(t *testing.T, d time.Duration) {
+	oldRetryInterval := watchRetryInterval
+	watchRetryInterval = d
+	t.Cleanup(func() {
+		watchRetryInterval = oldRetryInterval
+	})
+}
+
+func TestWatchLoop(t *testing.T) {
+	setWatchRetryInterval(t, 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watches := make(chan *watch.FakeWatcher, 10)
+	startWatch := func() (watch.Interface, error) {
+		w := watch.NewFake()
+		go func() {
+			// real watches are closed when the context gets cancelled
+			<-ctx.Done()
+			w.Stop()
+		}()
+		watches <- w
+		return w, nil
+	}
+
+	ch := make(chan watchedObject)
+	done := make(chan struct{})
+	go func() {
+		watchLoop(ctx, "test", startWatch, ch)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	cm := uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: default
+`)
+
+	w := <-watches
+	w.Add(cm.ToUnstructured())
+	wo := <-ch
+	assert.Equal(t, cm.GetK8sRef(), wo.ref)
+	assert.NotNil(t, wo.o)
+
+	w.Delete(cm.ToUnstructured())
+	wo = <-ch
+	assert.Equal(t, cm.GetK8sRef(), wo.ref)
+	assert.Nil(t, wo.o)
+
+	// the watch must be restarted when closed by the API server
+	w.Stop()
+	select {
+	case <-watches:
+	case <-time.After(watchRetryInterval + 5*time.Second):
+		assert.Fail(t, "watch was not restarted")
+	}
+}
+
+func TestWatchLoopNoHotLoop(t *testing.T) {
+	setWatchRetryInterval(t, 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var count int32
+	startWatch := func() (watch.Interface, error) {
+		atomic.AddInt32(&count, 1)
+		// simulate a watch that is closed immediately
+		w := watch.NewFake()
+		w.Stop()
+		return w, nil
+	}
+
+	watchLoop(ctx, "test", startWatch, make(chan watchedObject))
+
+	// without delaying restarts, this would have been called many thousand times
+	assert.LessOrEqual(t, atomic.LoadInt32(&count), int32(6))
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&count), int32(2))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/vars"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"io/fs"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -34,6 +35,9 @@ type DeploymentItem struct {
 	Objects []*uo.UnstructuredObject
 	Tags    *utils.OrderedMap
 
+	// VarsCtx contains the vars used to render this item, including the item level vars
+	VarsCtx *vars.VarsCtx
+
 	RenderedSourceRootDir string
 	RelToSourceItemDir    string
 	RelToProjectItemDir   string
@@ -118,6 +122,7 @@ func (di *DeploymentItem) render(forSeal bool) error {
 	if err != nil {
 		return err
 	}
+	di.VarsCtx = varsCtx
 
 	var excludePatterns []string
 	if len(di.Project.Config.TemplateExcludes) != 0 {
@@ -559,8 +564,14 @@ func (di *DeploymentItem) postprocessObjects(images *Images) error {
 			o.SetK8sLabels(uo.CopyMergeStrMap(o.GetK8sLabels(), commonLabels))
 			o.SetK8sAnnotations(uo.CopyMergeStrMap(o.GetK8sAnnotations(), commonAnnotations))
 
-			// Resolve image placeholders
-			err := images.ResolvePlaceholders(di.ctx.K, o, di.RelRenderedDir, di.Tags.ListKeys())
+			// Rewrite images from plain manifests and Helm charts
+			err := images.RewritePlainImages(o, di.RelRenderedDir, di.Tags.ListKeys())
+			if err != nil {
+				errList = append(errList, err)
+			}
+
+			// Resolve image placeholders, which also applies image rewrites to the resolved images
+			err = images.ResolvePlaceholders(di.ctx.K, o, di.RelRenderedDir, di.Tags.ListKeys())
 			if err != nil {
 				errList = append(errList, err)
 			}
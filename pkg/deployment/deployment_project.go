@@ -292,3 +292,57 @@ func (p *DeploymentProject) GetIgnoreForDiffs(ignoreTags, ignoreLabels, ignoreAn
 	}
 	return ret
 }
+
+func (p *DeploymentProject) GetNormalizeForDiffs() []*types.NormalizeForDiffItemConfig {
+	var ret []*types.NormalizeForDiffItemConfig
+	for _, e := range p.getParents() {
+		ret = append(ret, e.p.Config.NormalizeForDiff...)
+	}
+	return ret
+}
+
+var defaultProtectedKinds = []*types.ProtectedKindConfig{
+	{Kind: "PersistentVolumeClaim"},
+	{Kind: "Namespace"},
+}
+
+// GetProtectedKinds returns the kinds that are protected from being deleted by the delete and prune commands. If no
+// project in the hierarchy configures protectedKinds, PersistentVolumeClaims and Namespaces are protected by default.
+func (p *DeploymentProject) GetProtectedKinds() []*types.ProtectedKindConfig {
+	var ret []*types.ProtectedKindConfig
+	configured := false
+	for _, e := range p.getParents() {
+		if e.p.Config.ProtectedKinds != nil {
+			configured = true
+		}
+		ret = append(ret, e.p.Config.ProtectedKinds...)
+	}
+	if !configured {
+		return GetDefaultProtectedKinds()
+	}
+	return ret
+}
+
+func GetDefaultProtectedKinds() []*types.ProtectedKindConfig {
+	return defaultProtectedKinds
+}
+
+// GetDeleteOrder returns the deleteOrder configuration of the nearest project in the hierarchy that configures it
+func (p *DeploymentProject) GetDeleteOrder() *types.DeleteOrderConfig {
+	for _, e := range p.getParents() {
+		if e.p.Config.DeleteOrder != nil {
+			return e.p.Config.DeleteOrder
+		}
+	}
+	return nil
+}
+
+// GetReadinessRules returns the readiness rules of this project and all included sub-projects. Rules of the current
+// project come first, so that these take precedence over rules of sub-projects.
+func (p *DeploymentProject) GetReadinessRules() []*types.ReadinessRuleConfig {
+	var ret []*types.ReadinessRuleConfig
+	for _, c := range p.getChildren(true, true) {
+		ret = append(ret, c.Config.ReadinessRules...)
+	}
+	return ret
+}
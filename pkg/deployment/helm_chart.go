@@ -20,7 +20,6 @@ import (
 	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/registry"
-	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"os"
@@ -306,10 +305,8 @@ func (c *helmChart) doRender(ctx context.Context, k *k8s.K8sCluster) error {
 	}
 
 	if !client.DisableHooks {
+		// test hooks are rendered as well, but they are only applied by the helm-test command
 		for _, m := range rel.Hooks {
-			if isTestHook(m) {
-				continue
-			}
 			parsedHooks, err := c.parseRenderedManifests(m.Manifest)
 			if err != nil {
 				return err
@@ -379,15 +376,6 @@ func checkIfInstallable(ch *chart.Chart) error {
 	return errors.Errorf("%s charts are not installable", ch.Metadata.Type)
 }
 
-func isTestHook(h *release.Hook) bool {
-	for _, e := range h.Events {
-		if e == release.HookTest {
-			return true
-		}
-	}
-	return false
-}
-
 func (c *helmChart) Save() error {
 	return yaml.WriteYamlFile(c.configFile, c.Config)
 }
@@ -0,0 +1,253 @@
+package deployment
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type imageRewrite struct {
+	prefix      *string
+	regex       *regexp.Regexp
+	replacement string
+
+	// output matches images produced by a regex rewrite, with one capture group per group reference found in
+	// replacement. outputRefs holds the referenced group (name or number) of each of these capture groups.
+	output     *regexp.Regexp
+	outputRefs []string
+}
+
+var containerListKeys = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// SetImageRewrites configures the rewrites that are applied to all images, including images resolved via
+// 'images.get_image(...)' and images found in plain manifests and Helm charts. The first matching rewrite wins.
+func (images *Images) SetImageRewrites(rewrites []types.ImageRewrite) error {
+	images.rewrites = nil
+	for _, r := range rewrites {
+		x := imageRewrite{
+			prefix:      r.Prefix,
+			replacement: r.Replacement,
+		}
+		if r.Regex != nil {
+			re, err := regexp.Compile(*r.Regex)
+			if err != nil {
+				return fmt.Errorf("invalid image rewrite regex %s: %w", *r.Regex, err)
+			}
+			x.regex = re
+			x.output, x.outputRefs = buildRewriteOutputRegex(r.Replacement)
+		}
+		images.rewrites = append(images.rewrites, x)
+	}
+	return nil
+}
+
+// RewriteImage applies the first matching image rewrite to the given image. The second return value is false if
+// no rewrite matched.
+func (images *Images) RewriteImage(image string) (string, bool) {
+	for _, r := range images.rewrites {
+		if r.prefix != nil {
+			if strings.HasPrefix(image, *r.prefix) {
+				return r.replacement + image[len(*r.prefix):], true
+			}
+		} else if r.regex.MatchString(image) {
+			return r.regex.ReplaceAllString(image, r.replacement), true
+		}
+	}
+	return image, false
+}
+
+// RewritePlainImages applies image rewrites to all container images of the given object which are not resolved
+// via 'images.get_image(...)'. Rewritten images are recorded in the seen images.
+func (images *Images) RewritePlainImages(o *uo.UnstructuredObject, deploymentDir string, tags []string) error {
+	if len(images.rewrites) == 0 {
+		return nil
+	}
+
+	ref := o.GetK8sRef()
+	deployment := fmt.Sprintf("%s/%s", ref.GVK.Kind, ref.Name)
+
+	return uo.NewObjectIterator(o.Object).IterateLeafs(func(it *uo.ObjectIterator) error {
+		if it.Key() != "image" {
+			return nil
+		}
+		image, ok := it.Value().(string)
+		if !ok || strings.Contains(image, beginPlaceholder) {
+			return nil
+		}
+		keyPath := it.KeyPath()
+		if len(keyPath) < 3 {
+			return nil
+		}
+		if k, ok := keyPath[len(keyPath)-3].(string); !ok || !containerListKeys[k] {
+			return nil
+		}
+
+		rewritten, ok := images.RewriteImage(image)
+		if !ok {
+			return nil
+		}
+		err := it.SetValue(rewritten)
+		if err != nil {
+			return err
+		}
+
+		container := images.extractContainerName(it.Parent())
+		images.mutex.Lock()
+		images.seenImages = append(images.seenImages, types.FixedImage{
+			Image:          image,
+			ResultImage:    image,
+			RewrittenImage: &rewritten,
+			Namespace:      &ref.Namespace,
+			Object:         &ref,
+			Deployment:     &deployment,
+			Container:      &container,
+			DeployTags:     tags,
+			DeploymentDir:  &deploymentDir,
+		})
+		images.mutex.Unlock()
+		return nil
+	})
+}
+
+// buildRewriteOutputRegex builds a regex that matches all possible outputs of the given replacement template. Each
+// group reference ('$1', '$name', '${name}') is turned into a capture group.
+func buildRewriteOutputRegex(replacement string) (*regexp.Regexp, []string) {
+	var refs []string
+	pattern := "^"
+	for len(replacement) != 0 {
+		i := strings.Index(replacement, "$")
+		if i == -1 {
+			pattern += regexp.QuoteMeta(replacement)
+			break
+		}
+		pattern += regexp.QuoteMeta(replacement[:i])
+		replacement = replacement[i+1:]
+
+		if strings.HasPrefix(replacement, "$") {
+			pattern += regexp.QuoteMeta("$")
+			replacement = replacement[1:]
+			continue
+		}
+
+		var name string
+		if strings.HasPrefix(replacement, "{") {
+			end := strings.Index(replacement, "}")
+			if end == -1 {
+				// not a valid reference, so it's kept as is by regexp.Expand
+				pattern += regexp.QuoteMeta("$")
+				continue
+			}
+			name = replacement[1:end]
+			replacement = replacement[end+1:]
+		} else {
+			end := strings.IndexFunc(replacement, func(r rune) bool {
+				return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+			})
+			if end == -1 {
+				end = len(replacement)
+			}
+			name = replacement[:end]
+			replacement = replacement[end:]
+		}
+		if name == "" {
+			pattern += regexp.QuoteMeta("$")
+			continue
+		}
+		refs = append(refs, name)
+		pattern += "(.*)"
+	}
+	pattern += "$"
+	return regexp.MustCompile(pattern), refs
+}
+
+// reverse tries to compute the image which would be rewritten to the given image. This is only possible for prefix
+// rewrites and for regex rewrites where the regex solely consists of literals, anchors and capture groups which are
+// all referenced in the replacement. The first return value is the candidate, the second is true if the given image
+// looks like an output of this rewrite and the third is true if the candidate could be computed.
+func (r *imageRewrite) reverse(image string) (string, bool, bool) {
+	if r.prefix != nil {
+		if !strings.HasPrefix(image, r.replacement) {
+			return "", false, false
+		}
+		return *r.prefix + image[len(r.replacement):], true, true
+	}
+
+	m := r.output.FindStringSubmatch(image)
+	if m == nil {
+		return "", false, false
+	}
+	values := map[string]string{}
+	for i, ref := range r.outputRefs {
+		if v, ok := values[ref]; ok && v != m[i+1] {
+			// the same group is referenced multiple times with different values
+			return "", true, false
+		}
+		values[ref] = m[i+1]
+	}
+
+	re, err := syntax.Parse(r.regex.String(), syntax.Perl)
+	if err != nil {
+		return "", true, false
+	}
+	var parts []*syntax.Regexp
+	if re.Op == syntax.OpConcat {
+		parts = re.Sub
+	} else {
+		parts = []*syntax.Regexp{re}
+	}
+
+	var ret strings.Builder
+	for _, p := range parts {
+		switch p.Op {
+		case syntax.OpBeginText, syntax.OpBeginLine, syntax.OpEndText, syntax.OpEndLine, syntax.OpEmptyMatch:
+		case syntax.OpLiteral:
+			if p.Flags&syntax.FoldCase != 0 {
+				return "", true, false
+			}
+			ret.WriteString(string(p.Rune))
+		case syntax.OpCapture:
+			v, ok := values[p.Name]
+			if !ok || p.Name == "" {
+				v, ok = values[strconv.Itoa(p.Cap)]
+			}
+			if !ok {
+				return "", true, false
+			}
+			ret.WriteString(v)
+		default:
+			return "", true, false
+		}
+	}
+	return ret.String(), true, true
+}
+
+// UnrewriteImage reverses the image rewrites for an image which was rewritten before, e.g. an image read from an
+// already deployed object. The second return value is false if the image is not the output of any rewrite. The
+// third return value is false if the image looks like the output of a rewrite that can not be reversed.
+func (images *Images) UnrewriteImage(image string) (string, bool, bool) {
+	isOutput := false
+	for i := range images.rewrites {
+		candidate, matched, ok := images.rewrites[i].reverse(image)
+		if !matched {
+			continue
+		}
+		isOutput = true
+		if !ok {
+			continue
+		}
+		// verify the candidate, as the rewrite might not match it or another rewrite might take precedence
+		if x, ok := images.RewriteImage(candidate); ok && x == image {
+			return candidate, true, true
+		}
+	}
+	return image, isOutput, !isOutput
+}
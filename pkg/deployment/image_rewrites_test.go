@@ -0,0 +1,180 @@
+package deployment
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestRewriteImage(t *testing.T) {
+	prefix := "docker.io/"
+	regex := `^quay\.io/(.*)$`
+
+	images, _ := NewImages(nil, false, true)
+	err := images.SetImageRewrites([]types.ImageRewrite{
+		{Prefix: &prefix, Replacement: "mirror.local/docker/"},
+		{Regex: &regex, Replacement: "mirror.local/quay/$1"},
+	})
+	assert.NoError(t, err)
+
+	x, ok := images.RewriteImage("docker.io/library/nginx:1.23")
+	assert.True(t, ok)
+	assert.Equal(t, "mirror.local/docker/library/nginx:1.23", x)
+
+	x, ok = images.RewriteImage("quay.io/prometheus/node-exporter:v1.5.0")
+	assert.True(t, ok)
+	assert.Equal(t, "mirror.local/quay/prometheus/node-exporter:v1.5.0", x)
+
+	x, ok = images.RewriteImage("ghcr.io/kluctl/kluctl:v2.19.0")
+	assert.False(t, ok)
+	assert.Equal(t, "ghcr.io/kluctl/kluctl:v2.19.0", x)
+}
+
+func TestRewritePlainImages(t *testing.T) {
+	prefix := "docker.io/"
+
+	images, _ := NewImages(nil, false, true)
+	err := images.SetImageRewrites([]types.ImageRewrite{
+		{Prefix: &prefix, Replacement: "mirror.local/"},
+	})
+	assert.NoError(t, err)
+
+	o := uo.FromMap(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "d",
+			"namespace": "ns",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{
+						map[string]interface{}{"name": "init", "image": "docker.io/busybox"},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{"name": "c1", "image": "docker.io/nginx:1.23"},
+						map[string]interface{}{"name": "c2", "image": "ghcr.io/other:1.0"},
+					},
+				},
+			},
+		},
+	})
+
+	err = images.RewritePlainImages(o, "dir", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "mirror.local/busybox", o.GetNestedObjectListNoErr("spec", "template", "spec", "initContainers")[0].Object["image"])
+	assert.Equal(t, "mirror.local/nginx:1.23", o.GetNestedObjectListNoErr("spec", "template", "spec", "containers")[0].Object["image"])
+	assert.Equal(t, "ghcr.io/other:1.0", o.GetNestedObjectListNoErr("spec", "template", "spec", "containers")[1].Object["image"])
+
+	seen := images.SeenImages(false)
+	assert.Len(t, seen, 2)
+	assert.Equal(t, "docker.io/busybox", seen[0].Image)
+	assert.Equal(t, "mirror.local/busybox", *seen[0].RewrittenImage)
+	assert.Equal(t, "c1", *seen[1].Container)
+}
+
+func TestUnrewriteImage(t *testing.T) {
+	prefix := "docker.io/"
+	regex1 := `^quay\.io/(.*)$`
+	regex2 := `^(ghcr\.io)/(?P<path>.*)$`
+	regex3 := `^gcr\.io/.*/(.*)$`
+
+	images, _ := NewImages(nil, false, true)
+	err := images.SetImageRewrites([]types.ImageRewrite{
+		{Prefix: &prefix, Replacement: "mirror.local/docker/"},
+		{Regex: &regex1, Replacement: "mirror.local/quay/$1"},
+		{Regex: &regex2, Replacement: "mirror.local/${1}/${path}"},
+		{Regex: &regex3, Replacement: "mirror.local/gcr/$1"},
+	})
+	assert.NoError(t, err)
+
+	for _, image := range []string{
+		"docker.io/library/nginx:1.23",
+		"quay.io/prometheus/node-exporter:v1.5.0",
+		"ghcr.io/kluctl/kluctl:v2.19.0@sha256:1234",
+	} {
+		rewritten, ok := images.RewriteImage(image)
+		assert.True(t, ok)
+		x, isOutput, ok := images.UnrewriteImage(rewritten)
+		assert.True(t, isOutput)
+		assert.True(t, ok)
+		assert.Equal(t, image, x)
+	}
+
+	x, isOutput, ok := images.UnrewriteImage("registry.k8s.io/pause:3.9")
+	assert.False(t, isOutput)
+	assert.True(t, ok)
+	assert.Equal(t, "registry.k8s.io/pause:3.9", x)
+
+	// the regex contains a wildcard which is not captured, so it can't be reversed
+	_, isOutput, ok = images.UnrewriteImage("mirror.local/gcr/pause:3.9")
+	assert.True(t, isOutput)
+	assert.False(t, ok)
+}
+
+func TestResolvePlaceholdersWithRewrite(t *testing.T) {
+	regex := `^(.*)$`
+
+	b, err := yaml.WriteYamlBytes(map[string]string{"image": "nginx", "latestVersion": ".*"})
+	assert.NoError(t, err)
+	placeholder := beginPlaceholder + base64.StdEncoding.EncodeToString(b) + endPlaceholder
+
+	deploy := func(k *k8s.K8sCluster, fixed bool) (string, []types.FixedImage) {
+		images, _ := NewImages(nil, false, true)
+		err := images.SetImageRewrites([]types.ImageRewrite{
+			{Regex: &regex, Replacement: "mirror.local/$1"},
+		})
+		assert.NoError(t, err)
+		if fixed {
+			images.AddFixedImage(types.FixedImage{Image: "nginx", ResultImage: "nginx:1.23"})
+		}
+
+		o := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p1
+  namespace: default
+spec:
+  containers:
+  - name: c1
+`)
+		_ = o.SetNestedField(placeholder, "spec", "containers", 0, "image")
+
+		err = images.ResolvePlaceholders(k, o, "dir", nil)
+		assert.NoError(t, err)
+		image, _, _ := o.GetNestedString("spec", "containers", 0, "image")
+		return image, images.SeenImages(false)
+	}
+
+	// initial deployment, where no object exists yet
+	k, err := k8s.NewK8sCluster(context.TODO(), k8s.NewFakeClientFactory(), false)
+	assert.NoError(t, err)
+	image, seen := deploy(k, true)
+	assert.Equal(t, "mirror.local/nginx:1.23", image)
+
+	// deploy twice more, this time re-using the deployed image
+	for i := 0; i < 2; i++ {
+		k, err = k8s.NewK8sCluster(context.TODO(), k8s.NewFakeClientFactory(&corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: "p1", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "c1", Image: image}}},
+		}), false)
+		assert.NoError(t, err)
+
+		image, seen = deploy(k, false)
+		assert.Equal(t, "mirror.local/nginx:1.23", image)
+		assert.Len(t, seen, 1)
+		assert.Equal(t, "nginx:1.23", seen[0].ResultImage)
+		assert.Equal(t, "nginx:1.23", *seen[0].DeployedImage)
+		assert.Equal(t, "mirror.local/nginx:1.23", *seen[0].RewrittenImage)
+	}
+}
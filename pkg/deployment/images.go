@@ -2,6 +2,7 @@ package deployment
 
 import (
 	"bytes"
+	"crypto"
 	"encoding/base64"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
@@ -22,8 +23,13 @@ type Images struct {
 	rh           *registries.RegistryHelper
 	updateImages bool
 	offline      bool
+	pinDigests   bool
 	fixedImages  []types.FixedImage
 	seenImages   []types.FixedImage
+	catalog      map[string]types.ImageCatalogEntry
+	listedTags   map[string][]string
+	digests      map[string]map[string]string
+	rewrites     []imageRewrite
 	mutex        sync.Mutex
 
 	registryCache utils.ThreadSafeMultiCache
@@ -48,13 +54,50 @@ func (images *Images) PrependFixedImages(fis []types.FixedImage) {
 	images.fixedImages = newFixedImages
 }
 
+// SetImageCatalog causes all tag lookups to be served from the given catalog instead of the image registries.
+// Images which are not part of the catalog will cause an error.
+func (images *Images) SetImageCatalog(catalog *types.ImageCatalog) {
+	images.catalog = map[string]types.ImageCatalogEntry{}
+	for _, e := range catalog.Images {
+		images.catalog[e.Image] = e
+	}
+}
+
+// SetPinDigests enables resolving of all images to their manifest digests. Resolved images are then
+// written as 'image:tag@sha256:...'.
+func (images *Images) SetPinDigests(pinDigests bool) {
+	images.pinDigests = pinDigests
+}
+
+// ImageCatalog returns a catalog with the tags of all images that were looked up so far.
+func (images *Images) ImageCatalog() *types.ImageCatalog {
+	images.mutex.Lock()
+	defer images.mutex.Unlock()
+
+	ret := &types.ImageCatalog{}
+	for image, tags := range images.listedTags {
+		tags2 := append([]string{}, tags...)
+		sort.Strings(tags2)
+		ret.Images = append(ret.Images, types.ImageCatalogEntry{
+			Image:   image,
+			Tags:    tags2,
+			Digests: images.digests[image],
+		})
+	}
+	sort.Slice(ret.Images, func(i, j int) bool {
+		return ret.Images[i].Image < ret.Images[j].Image
+	})
+	return ret
+}
+
 func (images *Images) SeenImages(simple bool) []types.FixedImage {
 	var ret []types.FixedImage
 	for _, fi := range images.seenImages {
 		if simple {
 			ret = append(ret, types.FixedImage{
-				Image:       fi.Image,
-				ResultImage: fi.ResultImage,
+				Image:          fi.Image,
+				ResultImage:    fi.ResultImage,
+				RewrittenImage: fi.RewrittenImage,
 			})
 		} else {
 			ret = append(ret, fi)
@@ -86,9 +129,13 @@ func (images *Images) GetFixedImage(image string, namespace string, deployment s
 	return nil
 }
 
-func (images *Images) GetLatestImageFromRegistry(image string, latestVersion string) (*string, error) {
-	if images.offline {
-		return nil, nil
+func (images *Images) listImageTags(image string) ([]string, error) {
+	if images.catalog != nil {
+		e, ok := images.catalog[image]
+		if !ok {
+			return nil, fmt.Errorf("image %s is not part of the image catalog", image)
+		}
+		return e.Tags, nil
 	}
 
 	ret, err := images.registryCache.Get(image, "tag", func() (interface{}, error) {
@@ -98,6 +145,25 @@ func (images *Images) GetLatestImageFromRegistry(image string, latestVersion str
 		return nil, err
 	}
 	tags, _ := ret.([]string)
+	return tags, nil
+}
+
+func (images *Images) GetLatestImageFromRegistry(image string, latestVersion string) (*string, error) {
+	if images.offline && images.catalog == nil {
+		return nil, nil
+	}
+
+	tags, err := images.listImageTags(image)
+	if err != nil {
+		return nil, err
+	}
+
+	images.mutex.Lock()
+	if images.listedTags == nil {
+		images.listedTags = map[string][]string{}
+	}
+	images.listedTags[image] = tags
+	images.mutex.Unlock()
 
 	if len(tags) == 0 {
 		return nil, nil
@@ -118,6 +184,67 @@ func (images *Images) GetLatestImageFromRegistry(image string, latestVersion str
 	return &result, nil
 }
 
+func splitImageTag(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i == -1 || i < strings.LastIndex(image, "/") {
+		return image, "latest"
+	}
+	return image[:i], image[i+1:]
+}
+
+// ImageRepository returns the repository part of the given image, without tag and digest
+func ImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	repo, _ := splitImageTag(image)
+	return repo
+}
+
+// VerifyImageSignature verifies the cosign compatible signature of the given image against the given keys
+func (images *Images) VerifyImageSignature(image string, keys []crypto.PublicKey) error {
+	if images.offline {
+		return fmt.Errorf("can not verify signature of image %s while in offline mode", image)
+	}
+	return images.rh.VerifyImageSignature(image, keys)
+}
+
+func (images *Images) getImageDigest(image string) (string, error) {
+	repo, tag := splitImageTag(image)
+
+	var digest string
+	if images.catalog != nil {
+		e, ok := images.catalog[repo]
+		if !ok || e.Digests[tag] == "" {
+			return "", fmt.Errorf("digest for image %s is not part of the image catalog", image)
+		}
+		digest = e.Digests[tag]
+	} else {
+		if images.offline {
+			return "", fmt.Errorf("can not resolve digest for image %s while in offline mode", image)
+		}
+		ret, err := images.registryCache.Get(image, "digest", func() (interface{}, error) {
+			return images.rh.GetImageDigest(image)
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve digest for image %s: %w", image, err)
+		}
+		digest = ret.(string)
+	}
+
+	images.mutex.Lock()
+	if images.digests == nil {
+		images.digests = map[string]map[string]string{}
+	}
+	if images.digests[repo] == nil {
+		images.digests[repo] = map[string]string{}
+	}
+	images.digests[repo][tag] = digest
+	images.mutex.Unlock()
+
+	return digest, nil
+}
+
 const beginPlaceholder = "XXXXXbegin_get_image_"
 const endPlaceholder = "_end_get_imageXXXXX"
 
@@ -240,6 +367,17 @@ func (images *Images) ResolvePlaceholders(k *k8s.K8sCluster, o *uo.UnstructuredO
 			}
 		}
 
+		if deployed != nil {
+			// the deployed image was already rewritten, so we need to find the original image. Otherwise, the
+			// rewritten image would be treated as the original one and rewritten again.
+			original, _, ok := images.UnrewriteImage(*deployed)
+			if ok {
+				deployed = &original
+			} else {
+				deployed = nil
+			}
+		}
+
 		resultImage, err := images.resolveImage(ph, ref, deployment, deployed, deploymentDir, tags)
 		if err != nil {
 			return err
@@ -273,6 +411,23 @@ func (images *Images) resolveImage(ph placeHolder, ref k8s2.ObjectRef, deploymen
 		result = fixed
 	}
 
+	var digest *string
+	if result != nil && images.pinDigests {
+		if i := strings.Index(*result, "@"); i != -1 {
+			// already pinned, e.g. via fixed images or because the deployed image was pinned
+			x := (*result)[i+1:]
+			digest = &x
+		} else {
+			x, err := images.getImageDigest(*result)
+			if err != nil {
+				return nil, err
+			}
+			pinned := fmt.Sprintf("%s@%s", *result, x)
+			result = &pinned
+			digest = &x
+		}
+	}
+
 	si := types.FixedImage{
 		Image:         ph.Image,
 		DeployedImage: deployed,
@@ -284,9 +439,14 @@ func (images *Images) resolveImage(ph placeHolder, ref k8s2.ObjectRef, deploymen
 		VersionFilter: &ph.LatestVersion,
 		DeployTags:    tags,
 		DeploymentDir: &deploymentDir,
+		Digest:        digest,
 	}
 	if result != nil {
 		si.ResultImage = *result
+		if rewritten, ok := images.RewriteImage(*result); ok {
+			si.RewrittenImage = &rewritten
+			result = &rewritten
+		}
 	}
 	images.mutex.Lock()
 	images.seenImages = append(images.seenImages, si)
@@ -0,0 +1,167 @@
+package deployment
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func pushTestImage(t *testing.T, image string) string {
+	img, err := random.Image(1024, 1)
+	assert.NoError(t, err)
+	ref, err := name.ParseReference(image)
+	assert.NoError(t, err)
+	err = remote.Write(ref, img)
+	assert.NoError(t, err)
+	d, err := img.Digest()
+	assert.NoError(t, err)
+	return d.String()
+}
+
+func TestPinDigests(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	repo := fmt.Sprintf("%s/app", u.Host)
+	digest := pushTestImage(t, repo+":1.0.0")
+
+	rh := registries.NewRegistryHelper(context.Background())
+	rh.AddAuthEntry(registries.AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+
+	b, err := yaml.WriteYamlBytes(map[string]string{"image": repo, "latestVersion": "semver()"})
+	assert.NoError(t, err)
+	placeholder := beginPlaceholder + base64.StdEncoding.EncodeToString(b) + endPlaceholder
+
+	k, err := k8s.NewK8sCluster(context.TODO(), k8s.NewFakeClientFactory(), false)
+	assert.NoError(t, err)
+
+	resolve := func(tag string) (string, *Images, error) {
+		// the rewritten image does not exist, so resolving the digest only works if it happens before rewriting
+		regex := `^(.*)$`
+		images, _ := NewImages(rh, false, false)
+		images.SetPinDigests(true)
+		err := images.SetImageRewrites([]types.ImageRewrite{
+			{Regex: &regex, Replacement: "mirror.local/$1"},
+		})
+		assert.NoError(t, err)
+		images.AddFixedImage(types.FixedImage{Image: repo, ResultImage: repo + ":" + tag})
+
+		o := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p1
+  namespace: default
+spec:
+  containers:
+  - name: c1
+`)
+		_ = o.SetNestedField(placeholder, "spec", "containers", 0, "image")
+
+		err = images.ResolvePlaceholders(k, o, "dir", nil)
+		image, _, _ := o.GetNestedString("spec", "containers", 0, "image")
+		return image, images, err
+	}
+
+	image, images, err := resolve("1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("mirror.local/%s:1.0.0@%s", repo, digest), image)
+
+	seen := images.SeenImages(false)
+	assert.Len(t, seen, 1)
+	assert.Equal(t, fmt.Sprintf("%s:1.0.0@%s", repo, digest), seen[0].ResultImage)
+	assert.Equal(t, digest, *seen[0].Digest)
+
+	_, _, err = resolve("2.0.0")
+	assert.ErrorContains(t, err, fmt.Sprintf("failed to resolve digest for image %s:2.0.0", repo))
+}
+
+func TestImageCatalog(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	u, _ := url.Parse(s.URL)
+
+	repo1 := fmt.Sprintf("%s/app1", u.Host)
+	repo2 := fmt.Sprintf("%s/app2", u.Host)
+	pushTestImage(t, repo1+":1.0.0")
+	digest := pushTestImage(t, repo1+":1.1.0")
+	pushTestImage(t, repo2+":2.0.0")
+
+	rh := registries.NewRegistryHelper(context.Background())
+	rh.AddAuthEntry(registries.AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+
+	images, _ := NewImages(rh, false, false)
+
+	// images that are looked up multiple times must only appear once in the catalog
+	for _, repo := range []string{repo2, repo1, repo1} {
+		_, err := images.GetLatestImageFromRegistry(repo, "semver()")
+		assert.NoError(t, err)
+	}
+	_, err := images.getImageDigest(repo1 + ":1.1.0")
+	assert.NoError(t, err)
+
+	catalog := images.ImageCatalog()
+	assert.Equal(t, &types.ImageCatalog{
+		Images: []types.ImageCatalogEntry{
+			{Image: repo1, Tags: []string{"1.0.0", "1.1.0"}, Digests: map[string]string{"1.1.0": digest}},
+			{Image: repo2, Tags: []string{"2.0.0"}},
+		},
+	}, catalog)
+
+	y, err := yaml.WriteYamlString(catalog)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`images:
+  - image: %[1]s
+    tags:
+      - 1.0.0
+      - 1.1.0
+    digests:
+      1.1.0: %[3]s
+  - image: %[2]s
+    tags:
+      - 2.0.0
+`, repo1, repo2, digest), y)
+
+	// the exported catalog must be usable without access to the registry
+	s.Close()
+
+	var catalog2 types.ImageCatalog
+	err = yaml.ReadYamlString(y, &catalog2)
+	assert.NoError(t, err)
+
+	images, _ = NewImages(rh, false, true)
+	images.SetImageCatalog(&catalog2)
+
+	image, err := images.GetLatestImageFromRegistry(repo1, "semver()")
+	assert.NoError(t, err)
+	assert.Equal(t, repo1+":1.1.0", *image)
+	d, err := images.getImageDigest(repo1 + ":1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, digest, d)
+
+	_, err = images.GetLatestImageFromRegistry(fmt.Sprintf("%s/app3", u.Host), "semver()")
+	assert.ErrorContains(t, err, "is not part of the image catalog")
+	_, err = images.getImageDigest(repo1 + ":1.0.0")
+	assert.ErrorContains(t, err, "is not part of the image catalog")
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"strings"
+)
+
+const (
+	applyStrategyDefault                   = ""
+	applyStrategyCreateOnly                = "create-only"
+	applyStrategyReplace                   = "replace"
+	applyStrategyForceApply                = "force-apply"
+	applyStrategyRecreateOnImmutableChange = "recreate-on-immutable-change"
+)
+
+func getApplyStrategy(x *uo.UnstructuredObject) (string, error) {
+	a := x.GetK8sAnnotation("kluctl.io/apply-strategy")
+	if a == nil {
+		return applyStrategyDefault, nil
+	}
+	switch *a {
+	case applyStrategyCreateOnly, applyStrategyReplace, applyStrategyForceApply, applyStrategyRecreateOnImmutableChange:
+		return *a, nil
+	}
+	return "", fmt.Errorf("invalid kluctl.io/apply-strategy annotation '%s'", *a)
+}
+
+func hasApplyStrategy(x *uo.UnstructuredObject, strategy string) bool {
+	s, err := getApplyStrategy(x)
+	return err == nil && s == strategy
+}
+
+// isImmutableFieldError returns true if the error was caused by changing an immutable field
+func isImmutableFieldError(err error) bool {
+	if !errors.IsInvalid(err) && !errors.IsBadRequest(err) {
+		return false
+	}
+	s := err.Error()
+	return strings.Contains(s, "immutable") || strings.Contains(s, "may not be changed") || strings.Contains(s, "Forbidden: updates to")
+}
+
+// applyWithStrategy applies the object according to the kluctl.io/apply-strategy annotation. Returns false if the
+// default strategy should be used.
+func (a *ApplyUtil) applyWithStrategy(x *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject, hook bool) bool {
+	ref := x.GetK8sRef()
+
+	strategy, err := getApplyStrategy(x)
+	if err != nil {
+		a.HandleError(ref, err)
+		return true
+	}
+
+	switch strategy {
+	case applyStrategyCreateOnly:
+		if remoteObject == nil {
+			return false
+		}
+		// the object already exists, so we pretend that applying it resulted in the same object
+		a.handleResult(remoteObject, hook)
+		return true
+	case applyStrategyReplace:
+		if remoteObject == nil {
+			return false
+		}
+		x2 := x.Clone()
+		x2.SetK8sResourceVersion(remoteObject.GetK8sResourceVersion())
+		r, apiWarnings, err := a.k.UpdateObject(x2, k8s.UpdateOptions{
+			ForceDryRun: a.o.DryRun,
+		})
+		a.handleApiWarnings(ref, apiWarnings)
+		if err != nil {
+			a.HandleError(ref, err)
+			return true
+		}
+		a.handleResult(r, hook)
+		return true
+	case applyStrategyForceApply:
+		r, apiWarnings, err := a.k.PatchObject(x, k8s.PatchOptions{
+			ForceDryRun: a.o.DryRun,
+			ForceApply:  true,
+		})
+		a.handleApiWarnings(ref, apiWarnings)
+		if err != nil {
+			a.HandleError(ref, err)
+			return true
+		}
+		a.handleResult(r, hook)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"testing"
+)
+
+func TestGetApplyStrategy(t *testing.T) {
+	o := uo.New()
+	s, err := getApplyStrategy(o)
+	assert.NoError(t, err)
+	assert.Equal(t, applyStrategyDefault, s)
+
+	o.SetK8sAnnotation("kluctl.io/apply-strategy", "create-only")
+	s, err = getApplyStrategy(o)
+	assert.NoError(t, err)
+	assert.Equal(t, applyStrategyCreateOnly, s)
+	assert.True(t, hasApplyStrategy(o, applyStrategyCreateOnly))
+	assert.False(t, hasApplyStrategy(o, applyStrategyReplace))
+
+	o.SetK8sAnnotation("kluctl.io/apply-strategy", "invalid")
+	_, err = getApplyStrategy(o)
+	assert.Error(t, err)
+}
+
+func TestIsImmutableFieldError(t *testing.T) {
+	err := errors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "j1", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "template"), nil, "field is immutable"),
+	})
+	assert.True(t, isImmutableFieldError(err))
+
+	err = errors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "j1", field.ErrorList{
+		field.Required(field.NewPath("spec", "template"), ""),
+	})
+	assert.False(t, isImmutableFieldError(err))
+	assert.False(t, isImmutableFieldError(errors.NewNotFound(schema.GroupResource{}, "x")))
+}
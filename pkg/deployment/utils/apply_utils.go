@@ -32,6 +32,19 @@ type ApplyUtilOptions struct {
 	AbortOnError        bool
 	ReadinessTimeout    time.Duration
 	NoWait              bool
+
+	// RecreateObjects contains objects that are allowed to be deleted and re-created in case applying them fails due
+	// to changes to immutable fields. This is usually filled from the result of a previous dry-run.
+	RecreateObjects map[k8s2.ObjectRef]bool
+
+	// HookLogs enables collection of logs for successful hooks. Logs of failed hooks are always collected.
+	HookLogs bool
+
+	// AllowCommandHooks allows running external hooks of type 'command'
+	AllowCommandHooks bool
+
+	// ReadinessRules contains project specific readiness rules, which take precedence over the built-in readiness logic
+	ReadinessRules *validation.ReadinessRules
 }
 
 type ApplyUtil struct {
@@ -42,8 +55,11 @@ type ApplyUtil struct {
 	warningCount       int
 	appliedObjects     map[k8s2.ObjectRef]*uo.UnstructuredObject
 	appliedHookObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
+	hookLogs           map[k8s2.ObjectRef]string
 	deletedObjects     map[k8s2.ObjectRef]bool
 	deletedHookObjects map[k8s2.ObjectRef]bool
+	requireRecreation  map[k8s2.ObjectRef]bool
+	rolledBackObjects  []types.RolledBackObject
 	mutex              sync.Mutex
 
 	abortSignal *atomic.Value
@@ -91,8 +107,10 @@ func (ad *ApplyDeploymentsUtil) NewApplyUtil(ctx context.Context, statusCtx *sta
 		dew:                ad.dew,
 		appliedObjects:     map[k8s2.ObjectRef]*uo.UnstructuredObject{},
 		appliedHookObjects: map[k8s2.ObjectRef]*uo.UnstructuredObject{},
+		hookLogs:           map[k8s2.ObjectRef]string{},
 		deletedObjects:     map[k8s2.ObjectRef]bool{},
 		deletedHookObjects: map[k8s2.ObjectRef]bool{},
+		requireRecreation:  map[k8s2.ObjectRef]bool{},
 		abortSignal:        &ad.abortSignal,
 		ru:                 ad.ru,
 		k:                  ad.k,
@@ -185,6 +203,13 @@ func (a *ApplyUtil) retryApplyForceReplace(x *uo.UnstructuredObject, hook bool,
 	a.HandleWarning(ref, warn)
 	status.Warning(a.ctx, warn.Error())
 
+	a.recreateObject(x, hook)
+}
+
+// recreateObject deletes the object and then applies it again
+func (a *ApplyUtil) recreateObject(x *uo.UnstructuredObject, hook bool) {
+	ref := x.GetK8sRef()
+
 	if !a.DeleteObject(ref, hook) {
 		return
 	}
@@ -282,6 +307,9 @@ func (a *ApplyUtil) ApplyObject(x *uo.UnstructuredObject, replaced bool, hook bo
 
 	x = a.k.FixObjectForPatch(x)
 	remoteObject := a.ru.GetRemoteObject(ref)
+	if !replaced && a.applyWithStrategy(x, remoteObject, hook) {
+		return
+	}
 	var remoteNamespace *uo.UnstructuredObject
 	if ref.Namespace != "" {
 		remoteNamespace = a.ru.GetRemoteNamespace(ref.Namespace)
@@ -327,6 +355,8 @@ func (a *ApplyUtil) ApplyObject(x *uo.UnstructuredObject, replaced bool, hook bo
 		a.retryApplyWithConflicts(x, hook, remoteObject, err)
 	} else if errors.IsInternalError(err) {
 		a.HandleError(ref, err)
+	} else if isImmutableFieldError(err) && remoteObject != nil && !replaced && a.handleImmutableFieldError(x, hook) {
+		return
 	} else {
 		a.retryApplyWithReplace(x, hook, remoteObject, err)
 	}
@@ -363,7 +393,7 @@ func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) boo
 			a.HandleError(ref, err)
 			return false
 		}
-		v := validation.ValidateObject(a.k, o, false, false)
+		v := validation.ValidateObject(a.k, o, false, false, a.o.ReadinessRules)
 		if v.Ready {
 			if didLog {
 				a.sctx.InfoFallback("Finished waiting for %s (%ds elapsed)", ref.String(), elapsed)
@@ -377,6 +407,7 @@ func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) boo
 			for _, e := range v.Errors {
 				a.HandleError(ref, fmt.Errorf(e.Error))
 			}
+			a.collectObjectEvents(ref)
 			return false
 		}
 
@@ -404,6 +435,7 @@ func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) boo
 				}
 			}
 			a.HandleError(ref, err)
+			a.collectObjectEvents(ref)
 			return false
 		case <-a.ctx.Done():
 			err := fmt.Errorf("failed waiting for readiness of %s: %w", ref.String(), err)
@@ -455,6 +487,7 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 
 	var preHooks []*hook
 	var postHooks []*hook
+	failureHooks := h.DetermineHooks(d, []string{"on-deploy-failure"})
 	if initialDeploy {
 		preHooks = h.DetermineHooks(d, []string{"pre-deploy-initial", "pre-deploy"})
 		postHooks = h.DetermineHooks(d, []string{"post-deploy-initial", "post-deploy"})
@@ -497,7 +530,12 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 
 		ref := o.GetK8sRef()
 		a.sctx.Update(fmt.Sprintf("Applying object %s (%d of %d)", ref.String(), i+1, len(applyObjects)))
-		a.ApplyObject(o, false, false)
+		rolloutSteps, err := getRolloutSteps(d, o)
+		if err != nil {
+			a.HandleError(ref, err)
+		} else if rolloutSteps == nil || !a.applyWithRollout(o, rolloutSteps) {
+			a.ApplyObject(o, false, false)
+		}
 		a.sctx.Increment()
 		if time.Now().Sub(startTime) >= 10*time.Second || (didLog && i == len(applyObjects)-1) {
 			a.sctx.InfoFallback("...applied %d of %d objects", i+1, len(applyObjects))
@@ -510,11 +548,30 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 			a.WaitReadiness(o.GetK8sRef(), 0)
 		}
 	}
-	if a.abortSignal.Load().(bool) {
-		return
+
+	rolledBack := false
+	if a.errorCount != 0 && d.Config.OnFailure == "rollback" {
+		a.rollbackAppliedObjects()
+		rolledBack = true
+	}
+
+	aborted := a.abortSignal.Load().(bool)
+	if !aborted && !rolledBack {
+		h.RunHooks(postHooks)
+	}
+
+	if a.errorCount != 0 && len(failureHooks) != 0 {
+		// on-deploy-failure hooks are also run when the deployment got aborted, as these are usually meant to notify
+		// external systems
+		a.sctx.InfoFallback("Running %d on-deploy-failure hooks", len(failureHooks))
+		// failure hooks are not part of the initial total, as they only run when something went wrong
+		a.sctx.SetTotal(total + len(failureHooks))
+		h.runHooks(failureHooks, true)
 	}
 
-	h.RunHooks(postHooks)
+	if aborted {
+		return
+	}
 
 	finalStatus := ""
 	if len(a.appliedObjects) != 0 {
@@ -529,6 +586,9 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 	if len(a.deletedHookObjects) != 0 {
 		finalStatus += fmt.Sprintf(" Deleted %d hooks.", len(a.deletedHookObjects))
 	}
+	if rolledBack {
+		finalStatus += fmt.Sprintf(" Rolled back %d objects.", len(a.rolledBackObjects))
+	}
 	if a.errorCount != 0 {
 		finalStatus += fmt.Sprintf(" Encountered %d errors.", a.errorCount)
 	}
@@ -576,11 +636,15 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments() {
 		}
 	}
 
+	// deployments that were started since the last barrier
+	var barrierGroup []*deployment.DeploymentItem
+
 	for _, d_ := range a.deployments {
 		d := d_
 		if a.abortSignal.Load().(bool) {
 			break
 		}
+		barrierGroup = append(barrierGroup, d)
 
 		_ = sem.Acquire(context.Background(), 1)
 
@@ -610,6 +674,11 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments() {
 		if barrier {
 			sctx := status.StartWithOptions(a.ctx, status.WithStatus("Waiting on barrier..."), status.WithTotal(1))
 			wg.Wait()
+			if !a.checkBarrierHealth(sctx, barrierGroup) {
+				a.abortSignal.Store(true)
+				break
+			}
+			barrierGroup = nil
 			sctx.UpdateAndInfoFallback(fmt.Sprintf("Finished waiting"))
 			sctx.Success()
 		}
@@ -702,16 +771,32 @@ func (ad *ApplyDeploymentsUtil) GetAppliedHookObjects() []*types.RefAndObject {
 
 	var ret []*types.RefAndObject
 	for _, a := range ad.results {
-		for _, o := range a.appliedHookObjects {
+		for ref, o := range a.appliedHookObjects {
 			ret = append(ret, &types.RefAndObject{
 				Ref:    o.GetK8sRef(),
 				Object: o,
+				Logs:   a.hookLogs[ref],
 			})
 		}
 	}
 	return ret
 }
 
+// GetRequireRecreation returns all objects that failed to apply due to changes to immutable fields while in
+// dry-run mode. These need to be deleted and re-created when deploying for real.
+func (ad *ApplyDeploymentsUtil) GetRequireRecreation() map[k8s2.ObjectRef]bool {
+	ad.resultsMutex.Lock()
+	defer ad.resultsMutex.Unlock()
+
+	ret := make(map[k8s2.ObjectRef]bool)
+	for _, a := range ad.results {
+		for ref := range a.requireRecreation {
+			ret[ref] = true
+		}
+	}
+	return ret
+}
+
 func (ad *ApplyDeploymentsUtil) GetDeletedObjects() []k8s2.ObjectRef {
 	ad.resultsMutex.Lock()
 	defer ad.resultsMutex.Unlock()
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var workloadKinds = map[schema.GroupKind]bool{
+	{Group: "apps", Kind: "Deployment"}:  true,
+	{Group: "apps", Kind: "StatefulSet"}: true,
+	{Group: "apps", Kind: "DaemonSet"}:   true,
+	{Group: "apps", Kind: "ReplicaSet"}:  true,
+	{Group: "batch", Kind: "Job"}:        true,
+	{Group: "batch", Kind: "CronJob"}:    true,
+	{Group: "", Kind: "Pod"}:             true,
+}
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+type deletePhaseMatcher func(ref k8s2.ObjectRef) bool
+
+func isNamespaceRef(ref k8s2.ObjectRef) bool {
+	return ref.GVK.Group == "" && ref.GVK.Kind == "Namespace"
+}
+
+// buildDeletePhaseMatchers returns the matchers for all delete phases, in the order they must be deleted. The
+// configured phases come first, followed by the automatic rules: custom resources are deleted before workloads, so
+// that operators are still running and can handle finalizers, workloads are deleted before everything else (e.g.
+// Services and ConfigMaps) and CRDs are deleted after all custom resources. Namespaces are deleted first or last,
+// depending on the configuration.
+func buildDeletePhaseMatchers(config *types.DeleteOrderConfig, configuredPhases []map[schema.GroupKind]bool, isCustomResource func(gk schema.GroupKind) bool) []deletePhaseMatcher {
+	namespacesFirst := config != nil && config.Namespaces == "first"
+
+	var ret []deletePhaseMatcher
+	if namespacesFirst {
+		ret = append(ret, isNamespaceRef)
+	}
+	for _, p := range configuredPhases {
+		p := p
+		ret = append(ret, func(ref k8s2.ObjectRef) bool {
+			return p[ref.GVK.GroupKind()]
+		})
+	}
+	ret = append(ret, func(ref k8s2.ObjectRef) bool {
+		return isCustomResource(ref.GVK.GroupKind())
+	})
+	ret = append(ret, func(ref k8s2.ObjectRef) bool {
+		return workloadKinds[ref.GVK.GroupKind()]
+	})
+	ret = append(ret, func(ref k8s2.ObjectRef) bool {
+		return !isNamespaceRef(ref) && ref.GVK.GroupKind() != crdGroupKind
+	})
+	ret = append(ret, func(ref k8s2.ObjectRef) bool {
+		return ref.GVK.GroupKind() == crdGroupKind
+	})
+	ret = append(ret, isNamespaceRef)
+	return ret
+}
+
+// splitDeletePhases assigns each ref to the first phase with a matching matcher. Empty phases are omitted.
+func splitDeletePhases(refs []k8s2.ObjectRef, matchers []deletePhaseMatcher) [][]k8s2.ObjectRef {
+	done := make(map[k8s2.ObjectRef]bool)
+
+	var ret [][]k8s2.ObjectRef
+	for _, m := range matchers {
+		var phase []k8s2.ObjectRef
+		for _, ref := range refs {
+			if done[ref] || !m(ref) {
+				continue
+			}
+			done[ref] = true
+			phase = append(phase, ref)
+		}
+		if len(phase) != 0 {
+			ret = append(ret, phase)
+		}
+	}
+	return ret
+}
+
+// CalcDeletePhases splits the given objects into phases that are deleted one after the other. See
+// buildDeletePhaseMatchers for details about the order.
+func CalcDeletePhases(k *k8s.K8sCluster, refs []k8s2.ObjectRef, config *types.DeleteOrderConfig) [][]k8s2.ObjectRef {
+	var configuredPhases []map[schema.GroupKind]bool
+	if config != nil {
+		for _, filter := range config.Phases {
+			filter := filter
+			p := make(map[schema.GroupKind]bool)
+			for _, gvk := range k.Resources.GetFilteredPreferredGVKs(func(ar *v1.APIResource) bool {
+				for _, f := range filter {
+					if ar.Name == f || ar.Group == f || ar.Kind == f {
+						return true
+					}
+				}
+				return false
+			}) {
+				p[gvk.GroupKind()] = true
+			}
+			configuredPhases = append(configuredPhases, p)
+		}
+	}
+
+	isCustomResource := func(gk schema.GroupKind) bool {
+		return k.Resources.GetCRDForGK(gk) != nil
+	}
+
+	return splitDeletePhases(refs, buildDeletePhaseMatchers(config, configuredPhases, isCustomResource))
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+)
+
+func TestSplitDeletePhases(t *testing.T) {
+	ns := k8s2.NewObjectRef("", "v1", "Namespace", "ns", "")
+	cm := k8s2.NewObjectRef("", "v1", "ConfigMap", "cm", "ns")
+	svc := k8s2.NewObjectRef("", "v1", "Service", "svc", "ns")
+	deploy := k8s2.NewObjectRef("apps", "v1", "Deployment", "operator", "ns")
+	cr := k8s2.NewObjectRef("example.com", "v1", "MyResource", "cr", "ns")
+	crd := k8s2.NewObjectRef("apiextensions.k8s.io", "v1", "CustomResourceDefinition", "myresources.example.com", "")
+	refs := []k8s2.ObjectRef{ns, cm, svc, deploy, cr, crd}
+
+	isCustomResource := func(gk schema.GroupKind) bool {
+		return gk.Group == "example.com"
+	}
+
+	phases := splitDeletePhases(refs, buildDeletePhaseMatchers(nil, nil, isCustomResource))
+	assert.Equal(t, [][]k8s2.ObjectRef{
+		{cr},
+		{deploy},
+		{cm, svc},
+		{crd},
+		{ns},
+	}, phases)
+
+	config := &types.DeleteOrderConfig{Namespaces: "first"}
+	configuredPhases := []map[schema.GroupKind]bool{
+		{{Kind: "Service"}: true},
+	}
+	phases = splitDeletePhases(refs, buildDeletePhaseMatchers(config, configuredPhases, isCustomResource))
+	assert.Equal(t, [][]k8s2.ObjectRef{
+		{ns},
+		{svc},
+		{cr},
+		{deploy},
+		{cm},
+		{crd},
+	}, phases)
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
@@ -11,35 +13,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"strconv"
 	"sync"
+	"time"
 )
 
-// either names or apigroups
-var deleteOrder = [][]string{
-	// delete namespaces first
-	{
-		"Namespace",
-	},
-	// high level stuff from CRDs
-	{
-		"monitoring.coreos.com",
-		"kafka.strimzi.io",
-		"zookeeper.pravega.io",
-		"elasticsearch.k8s.elastic.co",
-		"cert-manager.io",
-		"bitnami.com",
-		"acid.zalan.do",
-	},
-	{
-		// generic high level stuff
-		"Deployment",
-		"StatefulSet",
-		"DaemonSet",
-		"Service",
-		"Ingress",
-	},
-	// and now everything else
-	nil,
-}
+// stuckDeletionTimeout is the time after which an object that is blocked by finalizers is reported as stuck
+const stuckDeletionTimeout = 5 * time.Minute
 
 func objectRefForExclusion(k *k8s.K8sCluster, ref k8s2.ObjectRef) k8s2.ObjectRef {
 	ref = k.Resources.FixNamespaceInRef(ref)
@@ -47,21 +25,11 @@ func objectRefForExclusion(k *k8s.K8sCluster, ref k8s2.ObjectRef) k8s2.ObjectRef
 	return ref
 }
 
-func filterObjectsForDelete(k *k8s.K8sCluster, objects []*uo.UnstructuredObject, apiFilter []string, inclusionHasTags bool, excludedObjects map[k8s2.ObjectRef]bool) ([]*uo.UnstructuredObject, error) {
-	filterFunc := func(ar *v1.APIResource) bool {
-		if len(apiFilter) == 0 {
-			return true
-		}
-		for _, f := range apiFilter {
-			if ar.Name == f || ar.Group == f || ar.Kind == f {
-				return true
-			}
-		}
-		return false
-	}
-
+func filterObjectsForDelete(k *k8s.K8sCluster, objects []*uo.UnstructuredObject, inclusionHasTags bool, excludedObjects map[k8s2.ObjectRef]bool) ([]*uo.UnstructuredObject, error) {
 	filteredResources := make(map[schema.GroupKind]bool)
-	for _, gvk := range k.Resources.GetFilteredPreferredGVKs(filterFunc) {
+	for _, gvk := range k.Resources.GetFilteredPreferredGVKs(func(ar *v1.APIResource) bool {
+		return true
+	}) {
 		filteredResources[gvk.GroupKind()] = true
 	}
 
@@ -131,24 +99,116 @@ func FindObjectsForDelete(k *k8s.K8sCluster, allClusterObjects []*uo.Unstructure
 		excludedObjectsMap[objectRefForExclusion(k, ref)] = true
 	}
 
+	l, err := filterObjectsForDelete(k, allClusterObjects, inclusionHasTags, excludedObjectsMap)
+	if err != nil {
+		return nil, err
+	}
+
 	var ret []k8s2.ObjectRef
+	for _, o := range l {
+		ret = append(ret, o.GetK8sRef())
+	}
+	return ret, nil
+}
+
+func isProtectedKind(gk schema.GroupKind, protectedKinds []*types.ProtectedKindConfig) bool {
+	for _, pk := range protectedKinds {
+		if pk.Group != nil && *pk.Group != gk.Group {
+			continue
+		}
+		if pk.Kind == gk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeleteProtected returns true if the object has the kluctl.io/delete-protection annotation set or if its kind is
+// listed in protectedKinds. Namespaces are not handled here, as these are only protected when they contain data.
+func isDeleteProtected(o *uo.UnstructuredObject, protectedKinds []*types.ProtectedKindConfig) bool {
+	protected, err := strconv.ParseBool(o.GetK8sAnnotations()["kluctl.io/delete-protection"])
+	if err == nil {
+		return protected
+	}
+	ref := o.GetK8sRef()
+	if isNamespaceRef(ref) {
+		return false
+	}
+	return isProtectedKind(ref.GVK.GroupKind(), protectedKinds)
+}
+
+// FilterProtectedObjects splits the given refs into objects that can be deleted and objects that are protected from
+// deletion. Namespaces are considered protected when their kind is protected and they contain PersistentVolumeClaims,
+// or when they contain other protected objects that would otherwise get deleted together with the namespace.
+func FilterProtectedObjects(k *k8s.K8sCluster, ru *RemoteObjectUtils, refs []k8s2.ObjectRef, protectedKinds []*types.ProtectedKindConfig) ([]k8s2.ObjectRef, []k8s2.ObjectRef, error) {
+	protected := make(map[k8s2.ObjectRef]bool)
+	protectedNamespaces := make(map[string]bool)
+
+	for _, ref := range refs {
+		if isNamespaceRef(ref) {
+			continue
+		}
+		o := ru.GetRemoteObject(ref)
+		if o == nil {
+			continue
+		}
+		if isDeleteProtected(o, protectedKinds) {
+			protected[ref] = true
+			if ref.Namespace != "" {
+				protectedNamespaces[ref.Namespace] = true
+			}
+		}
+	}
 
-	for _, filter := range deleteOrder {
-		l, err := filterObjectsForDelete(k, allClusterObjects, filter, inclusionHasTags, excludedObjectsMap)
+	pvcGvk := schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}
+	for _, ref := range refs {
+		if !isNamespaceRef(ref) {
+			continue
+		}
+		if protectedNamespaces[ref.Name] {
+			protected[ref] = true
+			continue
+		}
+		if o := ru.GetRemoteObject(ref); o != nil {
+			p, err := strconv.ParseBool(o.GetK8sAnnotations()["kluctl.io/delete-protection"])
+			if err == nil {
+				if p {
+					protected[ref] = true
+				}
+				continue
+			}
+		}
+		if !isProtectedKind(ref.GVK.GroupKind(), protectedKinds) {
+			continue
+		}
+		pvcs, _, err := k.ListObjects(pvcGvk, ref.Name, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		for _, o := range l {
-			ref := o.GetK8sRef()
-			excludedObjectsMap[objectRefForExclusion(k, ref)] = true
-			ret = append(ret, ref)
+		if len(pvcs) != 0 {
+			protected[ref] = true
 		}
 	}
 
-	return ret, nil
+	var allowedRefs, protectedRefs []k8s2.ObjectRef
+	for _, ref := range refs {
+		if protected[ref] {
+			protectedRefs = append(protectedRefs, ref)
+		} else {
+			allowedRefs = append(allowedRefs, ref)
+		}
+	}
+	return allowedRefs, protectedRefs, nil
 }
 
-func DeleteObjects(k *k8s.K8sCluster, refs []k8s2.ObjectRef, doWait bool) (*types.CommandResult, error) {
+// DeleteObjects deletes the given objects phase by phase. Objects inside a phase are deleted in parallel, and when
+// doWait is true, each phase waits for all its objects to vanish before the next phase starts. Objects inside
+// namespaces that got deleted in a previous phase (or the same phase) are skipped, as they are deleted via the namespace.
+//
+// Objects that are blocked by finalizers for longer than stuckDeletionTimeout result in errors that contain the
+// blocking finalizers. If removeFinalizersAfter is non-zero, the finalizers of such objects are forcefully removed
+// after the given duration instead, which is recorded as a warning.
+func DeleteObjects(k *k8s.K8sCluster, phases [][]k8s2.ObjectRef, doWait bool, removeFinalizersAfter time.Duration) (*types.CommandResult, error) {
 	var wg sync.WaitGroup
 	sem := semaphore.NewWeighted(8)
 
@@ -160,6 +220,11 @@ func DeleteObjects(k *k8s.K8sCluster, refs []k8s2.ObjectRef, doWait bool) (*type
 		mutex.Lock()
 		defer mutex.Unlock()
 
+		var stuckErr *k8s.StuckDeletionError
+		if errors.As(err, &stuckErr) {
+			err = fmt.Errorf("%w. Use --remove-finalizers-after to forcefully remove the finalizers", err)
+		}
+
 		if err == nil {
 			ret.DeletedObjects = append(ret.DeletedObjects, ref)
 		} else {
@@ -176,43 +241,44 @@ func DeleteObjects(k *k8s.K8sCluster, refs []k8s2.ObjectRef, doWait bool) (*type
 		}
 	}
 
-	for _, ref_ := range refs {
-		ref := ref_
-		if ref.GVK.GroupVersion().String() == "v1" && ref.GVK.Kind == "Namespace" {
-			namespaceNames[ref.Name] = true
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				_ = sem.Acquire(context.Background(), 1)
-				defer sem.Release(1)
-
-				apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !doWait, IgnoreNotFoundError: true})
-				handleResult(ref, apiWarnings, err)
-			}()
-		}
-	}
-	wg.Wait()
-
-	for _, ref_ := range refs {
-		ref := ref_
-		if ref.GVK.GroupVersion().String() == "v1" && ref.GVK.Kind == "Namespace" {
-			continue
-		}
-		if _, ok := namespaceNames[ref.Namespace]; ok {
-			// already deleted via namespace
-			continue
-		}
+	deleteRef := func(ref k8s2.ObjectRef) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			_ = sem.Acquire(context.Background(), 1)
 			defer sem.Release(1)
 
-			apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{NoWait: !doWait, IgnoreNotFoundError: true})
+			apiWarnings, err := k.DeleteSingleObject(ref, k8s.DeleteOptions{
+				NoWait:                !doWait,
+				IgnoreNotFoundError:   true,
+				StuckTimeout:          stuckDeletionTimeout,
+				RemoveFinalizersAfter: removeFinalizersAfter,
+			})
 			handleResult(ref, apiWarnings, err)
 		}()
 	}
-	wg.Wait()
+
+	for _, refs := range phases {
+		for _, ref := range refs {
+			if isNamespaceRef(ref) {
+				namespaceNames[ref.Name] = true
+				deleteRef(ref)
+			}
+		}
+		wg.Wait()
+
+		for _, ref := range refs {
+			if isNamespaceRef(ref) {
+				continue
+			}
+			if _, ok := namespaceNames[ref.Namespace]; ok {
+				// already deleted via namespace
+				continue
+			}
+			deleteRef(ref)
+		}
+		wg.Wait()
+	}
 
 	return &ret, nil
 }
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsDeleteProtected(t *testing.T) {
+	group := "apps"
+	protectedKinds := []*types.ProtectedKindConfig{
+		{Kind: "PersistentVolumeClaim"},
+		{Group: &group, Kind: "StatefulSet"},
+	}
+
+	newObject := func(group string, kind string) *uo.UnstructuredObject {
+		o := uo.New()
+		o.SetK8sGVKs(group, "v1", kind)
+		o.SetK8sName("o1")
+		return o
+	}
+
+	assert.True(t, isDeleteProtected(newObject("", "PersistentVolumeClaim"), protectedKinds))
+	assert.True(t, isDeleteProtected(newObject("apps", "StatefulSet"), protectedKinds))
+	assert.False(t, isDeleteProtected(newObject("other", "StatefulSet"), protectedKinds))
+	assert.False(t, isDeleteProtected(newObject("", "ConfigMap"), protectedKinds))
+	assert.False(t, isDeleteProtected(newObject("", "Namespace"), []*types.ProtectedKindConfig{{Kind: "Namespace"}}))
+
+	o := newObject("", "ConfigMap")
+	o.SetK8sAnnotation("kluctl.io/delete-protection", "true")
+	assert.True(t, isDeleteProtected(o, protectedKinds))
+
+	o = newObject("", "PersistentVolumeClaim")
+	o.SetK8sAnnotation("kluctl.io/delete-protection", "false")
+	assert.False(t, isDeleteProtected(o, protectedKinds))
+}
@@ -3,9 +3,11 @@ package utils
 import (
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sort"
 	"sync"
 	"time"
@@ -13,6 +15,7 @@ import (
 
 type diffUtil struct {
 	dew            *DeploymentErrorsAndWarnings
+	k              *k8s.K8sCluster
 	deployments    []*deployment.DeploymentItem
 	appliedObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
 	ru             *RemoteObjectUtils
@@ -20,6 +23,10 @@ type diffUtil struct {
 	IgnoreTags        bool
 	IgnoreLabels      bool
 	IgnoreAnnotations bool
+	ShowOwnership     bool
+
+	// RequireRecreation contains objects that failed to apply due to immutable field changes while in dry-run mode
+	RequireRecreation map[k8s2.ObjectRef]bool
 
 	remoteDiffObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
 	NewObjects        []*types.RefAndObject
@@ -27,9 +34,10 @@ type diffUtil struct {
 	mutex             sync.Mutex
 }
 
-func NewDiffUtil(dew *DeploymentErrorsAndWarnings, deployments []*deployment.DeploymentItem, ru *RemoteObjectUtils, appliedObjects map[k8s2.ObjectRef]*uo.UnstructuredObject) *diffUtil {
+func NewDiffUtil(dew *DeploymentErrorsAndWarnings, k *k8s.K8sCluster, deployments []*deployment.DeploymentItem, ru *RemoteObjectUtils, appliedObjects map[k8s2.ObjectRef]*uo.UnstructuredObject) *diffUtil {
 	return &diffUtil{
 		dew:            dew,
+		k:              k,
 		deployments:    deployments,
 		ru:             ru,
 		appliedObjects: appliedObjects,
@@ -47,6 +55,7 @@ func (u *diffUtil) Diff() {
 		}
 
 		ignoreForDiffs := d.Project.GetIgnoreForDiffs(u.IgnoreTags, u.IgnoreLabels, u.IgnoreAnnotations)
+		normalizeForDiffs := d.Project.GetNormalizeForDiffs()
 		for _, o := range d.Objects {
 			o := o
 			ref := o.GetK8sRef()
@@ -60,7 +69,7 @@ func (u *diffUtil) Diff() {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				u.diffObject(o, diffRef, ao, ro, ignoreForDiffs)
+				u.diffObject(o, diffRef, ao, ro, ignoreForDiffs, normalizeForDiffs)
 			}()
 		}
 	}
@@ -74,7 +83,7 @@ func (u *diffUtil) Diff() {
 	})
 }
 
-func (u *diffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef, ao *uo.UnstructuredObject, ro *uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig) {
+func (u *diffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef, ao *uo.UnstructuredObject, ro *uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig, normalizeForDiffs []*types.NormalizeForDiffItemConfig) {
 	if ao != nil && ro == nil {
 		u.mutex.Lock()
 		defer u.mutex.Unlock()
@@ -89,8 +98,12 @@ func (u *diffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef,
 		// did not apply? (e.g. in downscale command)
 		return
 	} else {
-		nao := diff.NormalizeObject(ao, ignoreForDiffs, lo)
-		nro := diff.NormalizeObject(ro, ignoreForDiffs, lo)
+		ao2 := ao.Clone()
+		ro2 := ro.Clone()
+		diff.NormalizeWithSchema(u.getSchema(lo.GetK8sGVK()), lo, ao2, ro2)
+
+		nao := diff.NormalizeObject(ao2, ignoreForDiffs, normalizeForDiffs, lo)
+		nro := diff.NormalizeObject(ro2, ignoreForDiffs, normalizeForDiffs, lo)
 		changes, err := diff.Diff(nro, nao)
 		if err != nil {
 			u.dew.AddError(lo.GetK8sRef(), err)
@@ -100,17 +113,50 @@ func (u *diffUtil) diffObject(lo *uo.UnstructuredObject, diffRef k8s2.ObjectRef,
 			return
 		}
 
+		var ownership []types.FieldOwnership
+		if u.ShowOwnership {
+			fields, err := diff.GetFieldOwnership(lo, ro)
+			if err != nil {
+				u.dew.AddWarning(lo.GetK8sRef(), err)
+			} else {
+				ownership = diff.FilterForeignFieldOwnership(fields)
+			}
+		}
+
+		immutableChanges := getImmutableChanges(lo.GetK8sGVK().GroupKind(), changes)
+		requiresRecreation := u.RequireRecreation[lo.GetK8sRef()] || len(immutableChanges) != 0
+
 		u.mutex.Lock()
 		defer u.mutex.Unlock()
 		u.ChangedObjects = append(u.ChangedObjects, &types.ChangedObject{
-			Ref:       diffRef,
-			NewObject: ao,
-			OldObject: ro,
-			Changes:   changes,
+			Ref:                diffRef,
+			NewObject:          ao,
+			OldObject:          ro,
+			Changes:            changes,
+			Ownership:          ownership,
+			RequiresRecreation: requiresRecreation,
+			ImmutableChanges:   immutableChanges,
 		})
 	}
 }
 
+// getSchema returns the CRD schema for the given GVK or falls back to the OpenAPI schema published by the API server.
+// Returns nil if no schema is available, in which case schema based normalization is skipped.
+func (u *diffUtil) getSchema(gvk schema.GroupVersionKind) *uo.UnstructuredObject {
+	if u.k == nil {
+		return nil
+	}
+	s, err := u.k.Resources.GetSchemaForGVK(gvk)
+	if err == nil && s != nil {
+		return s
+	}
+	s, err = u.k.Resources.GetOpenAPISchemaForGVK(gvk)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
 func (u *diffUtil) calcRemoteObjectsForDiff() {
 	u.remoteDiffObjects = make(map[k8s2.ObjectRef]*uo.UnstructuredObject)
 	for _, o := range u.ru.remoteObjects {
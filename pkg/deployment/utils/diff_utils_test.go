@@ -178,7 +178,7 @@ func TestDiff(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.dew = NewDeploymentErrorsAndWarnings()
 			test.ru = test.newRemoteObjects(test.dew)
-			test.du = NewDiffUtil(test.dew, test.newDeploymentItems(), test.ru, test.appliedObjectsMap())
+			test.du = NewDiffUtil(test.dew, nil, test.newDeploymentItems(), test.ru, test.appliedObjectsMap())
 			test.du.Diff()
 			test.a(t, test)
 		})
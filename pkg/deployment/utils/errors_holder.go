@@ -59,6 +59,30 @@ func (dew *DeploymentErrorsAndWarnings) AddError(ref k8s.ObjectRef, err error) {
 	m[de] = true
 }
 
+// SetErrorLogs attaches the given logs to all errors of the given object
+func (dew *DeploymentErrorsAndWarnings) SetErrorLogs(ref k8s.ObjectRef, logs string) {
+	dew.mutex.Lock()
+	defer dew.mutex.Unlock()
+	m := make(map[types.DeploymentError]bool)
+	for de := range dew.errors[ref] {
+		de.Logs = logs
+		m[de] = true
+	}
+	dew.errors[ref] = m
+}
+
+// SetErrorEvents attaches the given events to all errors of the given object
+func (dew *DeploymentErrorsAndWarnings) SetErrorEvents(ref k8s.ObjectRef, events string) {
+	dew.mutex.Lock()
+	defer dew.mutex.Unlock()
+	m := make(map[types.DeploymentError]bool)
+	for de := range dew.errors[ref] {
+		de.Events = events
+		m[de] = true
+	}
+	dew.errors[ref] = m
+}
+
 func (dew *DeploymentErrorsAndWarnings) AddApiWarnings(ref k8s.ObjectRef, warnings []k8s2.ApiWarning) {
 	for _, w := range warnings {
 		dew.AddWarning(ref, fmt.Errorf(w.Text))
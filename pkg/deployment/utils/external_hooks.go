@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"io"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// externalHookRef returns the pseudo object ref that is used to report errors and warnings of external hooks. The
+// deployment item dir is used as namespace, so that hooks with the same name in different deployment items are
+// distinguishable.
+func externalHookRef(d *deployment.DeploymentItem, name string) k8s2.ObjectRef {
+	return k8s2.ObjectRef{
+		GVK:       schema.GroupVersionKind{Group: "kluctl.io", Kind: "ExternalHook"},
+		Name:      name,
+		Namespace: filepath.ToSlash(d.RelToSourceItemDir),
+	}
+}
+
+func (u *HooksUtil) getExternalHook(d *deployment.DeploymentItem, c *types.ExternalHookConfig) *hook {
+	ref := externalHookRef(d, c.Name)
+
+	hooks := make(map[string]bool)
+	for _, h := range c.Hooks {
+		if utils.FindStrInSlice(supportedKluctlHooks, h) == -1 {
+			u.a.HandleError(ref, fmt.Errorf("unsupported hook '%s'", h))
+			continue
+		}
+		hooks[h] = true
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	var timeout time.Duration
+	if c.Timeout != "" {
+		t, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			u.a.HandleError(ref, fmt.Errorf("failed to parse duration: %w", err))
+		} else {
+			timeout = t
+		}
+	}
+
+	return &hook{
+		external:     c,
+		externalItem: d,
+		hooks:        hooks,
+		weight:       c.Weight,
+		timeout:      timeout,
+	}
+}
+
+func (u *HooksUtil) runExternalHook(h *hook) {
+	ref := h.Ref()
+	if u.a.o.DryRun {
+		return
+	}
+
+	timeout := h.timeout
+	if timeout == 0 {
+		timeout = u.a.o.ReadinessTimeout
+	}
+	ctx := u.a.ctx
+	if timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output string
+	var err error
+	if h.external.Command != nil {
+		if !u.a.o.AllowCommandHooks {
+			u.a.HandleError(ref, fmt.Errorf("external hook %s is a command hook, which is only allowed when --allow-command-hooks is passed", h.external.Name))
+			return
+		}
+		var env []string
+		env, err = buildExternalHookEnv(h)
+		if err == nil {
+			output, err = runExternalHookCommand(ctx, h.external.Command, h.externalItem.RenderedDir, env)
+		}
+	} else {
+		output, err = callExternalHookHttp(ctx, h.external.Http)
+	}
+	output = truncateLogs(output, hookLogsMaxBytes)
+
+	if err != nil {
+		u.a.HandleError(ref, fmt.Errorf("external hook %s failed: %w", h.external.Name, err))
+		if output != "" {
+			u.a.dew.SetErrorLogs(ref, output)
+		}
+		return
+	}
+	if u.a.o.HookLogs && output != "" {
+		u.a.mutex.Lock()
+		u.a.hookLogs[ref] = output
+		u.a.mutex.Unlock()
+	}
+}
+
+// buildExternalHookEnv exposes the vars of the deployment item as JSON via KLUCTL_VARS, together with the
+// explicitly configured environment variables
+func buildExternalHookEnv(h *hook) ([]string, error) {
+	env := os.Environ()
+
+	varsCtx := h.externalItem.VarsCtx
+	if varsCtx == nil {
+		varsCtx = h.externalItem.Project.VarsCtx
+	}
+	if varsCtx != nil {
+		b, err := json.Marshal(varsCtx.Vars.Object)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, fmt.Sprintf("KLUCTL_VARS=%s", string(b)))
+	}
+	env = append(env, fmt.Sprintf("KLUCTL_HOOK_NAME=%s", h.external.Name))
+	env = append(env, fmt.Sprintf("KLUCTL_DEPLOYMENT_DIR=%s", h.externalItem.RelToSourceItemDir))
+
+	for k, v := range h.external.Command.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env, nil
+}
+
+func runExternalHookCommand(ctx context.Context, c *types.ExternalHookCommandConfig, dir string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	err := cmd.Run()
+	return buf.String(), err
+}
+
+func callExternalHookHttp(ctx context.Context, c *types.ExternalHookHttpConfig) (string, error) {
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.Url, strings.NewReader(c.Body))
+	if err != nil {
+		return "", err
+	}
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, hookLogsMaxBytes*2))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return string(body), fmt.Errorf("http request returned status %s", resp.Status)
+	}
+	return string(body), nil
+}
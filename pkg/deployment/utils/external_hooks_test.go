@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunExternalHookCommand(t *testing.T) {
+	c := &types.ExternalHookCommandConfig{
+		Command: []string{"sh", "-c", "echo $KLUCTL_HOOK_NAME $X"},
+	}
+	output, err := runExternalHookCommand(context.Background(), c, "", []string{"KLUCTL_HOOK_NAME=h1", "X=x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "h1 x\n", output)
+
+	c.Command = []string{"sh", "-c", "echo failed; exit 1"}
+	output, err = runExternalHookCommand(context.Background(), c, "", nil)
+	assert.Error(t, err)
+	assert.Equal(t, "failed\n", output)
+}
+
+func TestCallExternalHookHttp(t *testing.T) {
+	var gotMethod, gotBody, gotHeader string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotMethod = r.Method
+		gotBody = string(b)
+		gotHeader = r.Header.Get("X-Test")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("error"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer s.Close()
+
+	c := &types.ExternalHookHttpConfig{
+		Url:     s.URL + "/ok",
+		Headers: map[string]string{"X-Test": "h"},
+		Body:    `{"version": "1.0"}`,
+	}
+	output, err := callExternalHookHttp(context.Background(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", output)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, `{"version": "1.0"}`, gotBody)
+	assert.Equal(t, "h", gotHeader)
+
+	c.Url = s.URL + "/fail"
+	c.Method = http.MethodPut
+	output, err = callExternalHookHttp(context.Background(), c)
+	assert.Error(t, err)
+	assert.Equal(t, "error", output)
+	assert.Equal(t, http.MethodPut, gotMethod)
+}
+
+func TestValidateExternalHookConfig(t *testing.T) {
+	for _, s := range []string{
+		"name: h1\nhooks: [post-deploy]\ncommand:\n  command: []\n",
+		"name: h1\nhooks: [post-deploy]\ncommand: {}\n",
+		"name: h1\nhooks: [post-deploy]\n",
+	} {
+		var c types.ExternalHookConfig
+		err := yaml.ReadYamlString(s, &c)
+		assert.Error(t, err, s)
+	}
+
+	var c types.ExternalHookConfig
+	err := yaml.ReadYamlString("name: h1\nhooks: [post-deploy]\ncommand:\n  command: [\"true\"]\n", &c)
+	assert.NoError(t, err)
+}
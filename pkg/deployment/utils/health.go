@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IsHookObject returns true if the object is a kluctl or Helm hook. Hooks are not considered when calculating the
+// health of deployment items.
+func IsHookObject(o *uo.UnstructuredObject) bool {
+	return o.GetK8sAnnotation("kluctl.io/hook") != nil || o.GetK8sAnnotation("helm.sh/hook") != nil
+}
+
+// CalcDeploymentItemHealth calculates the aggregated health of all objects of the given deployment item. getObject
+// must return nil for objects that do not exist. Returns nil if the deployment item has no objects.
+func CalcDeploymentItemHealth(d *deployment.DeploymentItem, getObject func(ref k8s2.ObjectRef) *uo.UnstructuredObject, getHealth func(ref k8s2.ObjectRef, o *uo.UnstructuredObject) types.ObjectHealth) *types.DeploymentItemHealth {
+	var objects []types.ObjectHealth
+	for _, o := range d.Objects {
+		if IsHookObject(o) || utils.ParseBoolOrFalse(o.GetK8sAnnotation("kluctl.io/delete")) {
+			continue
+		}
+		ref := o.GetK8sRef()
+		objects = append(objects, getHealth(ref, getObject(ref)))
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	ret := &types.DeploymentItemHealth{
+		Dir:    d.RelToProjectItemDir,
+		Health: validation.AggregateHealth(objects),
+	}
+	for _, x := range objects {
+		if x.Health != types.HealthHealthy {
+			ret.Objects = append(ret.Objects, x)
+		}
+	}
+	return ret
+}
+
+func (a *ApplyDeploymentsUtil) calcHealth(deployments []*deployment.DeploymentItem) []types.DeploymentItemHealth {
+	getObject := func(ref k8s2.ObjectRef) *uo.UnstructuredObject {
+		o, apiWarnings, err := a.k.GetSingleObject(ref)
+		a.dew.AddApiWarnings(ref, apiWarnings)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				a.dew.AddWarning(ref, fmt.Errorf("failed to get object for health check: %w", err))
+			}
+			return nil
+		}
+		return o
+	}
+	getHealth := func(ref k8s2.ObjectRef, o *uo.UnstructuredObject) types.ObjectHealth {
+		return validation.GetObjectHealth(a.k, ref, o, a.o.ReadinessRules)
+	}
+
+	var ret []types.DeploymentItemHealth
+	for _, d := range deployments {
+		if !d.CheckInclusionForDeploy() {
+			continue
+		}
+		h := CalcDeploymentItemHealth(d, getObject, getHealth)
+		if h != nil {
+			ret = append(ret, *h)
+		}
+	}
+	return ret
+}
+
+// GetHealth returns the aggregated health of all deployment items, as found on the cluster after the deployment
+func (a *ApplyDeploymentsUtil) GetHealth() []types.DeploymentItemHealth {
+	return a.calcHealth(a.deployments)
+}
+
+// checkBarrierHealth checks the health of all deployment items that were deployed before a barrier. Degraded objects
+// are reported as errors, so that the deployment does not continue past the barrier. Returns false if any of the
+// deployment items is degraded.
+func (a *ApplyDeploymentsUtil) checkBarrierHealth(sctx *status.StatusContext, deployments []*deployment.DeploymentItem) bool {
+	if a.o.DryRun || a.o.NoWait {
+		return true
+	}
+
+	sctx.Update("Checking health of deployments before barrier")
+	degraded := 0
+	for _, h := range a.calcHealth(deployments) {
+		if h.Health != types.HealthDegraded {
+			continue
+		}
+		degraded++
+		for _, o := range h.Objects {
+			if o.Health == types.HealthDegraded && !a.dew.HadError(o.Ref) {
+				a.dew.AddError(o.Ref, fmt.Errorf("object is degraded: %s", o.Message))
+			}
+		}
+	}
+	if degraded != 0 {
+		sctx.FailedWithMessage("%d deployments are degraded, not continuing after barrier", degraded)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+)
+
+const (
+	hookLogsTailLines = 50
+	// hookLogsMaxBytes is the maximum size of logs collected per container
+	hookLogsMaxBytes = 4096
+)
+
+// truncateLogs keeps the last maxBytes bytes of the given logs
+func truncateLogs(logs string, maxBytes int) string {
+	if len(logs) <= maxBytes {
+		return logs
+	}
+	logs = logs[len(logs)-maxBytes:]
+	if i := strings.IndexByte(logs, '\n'); i != -1 {
+		logs = logs[i+1:]
+	}
+	return "...(truncated)\n" + logs
+}
+
+// getHookPods returns the Pods that belong to the given hook. Only Pods and Jobs are supported.
+func (a *ApplyUtil) getHookPods(ref k8s2.ObjectRef) ([]*uo.UnstructuredObject, error) {
+	podGvk := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	switch ref.GVK.GroupKind() {
+	case schema.GroupKind{Kind: "Pod"}:
+		o, apiWarnings, err := a.k.GetSingleObject(ref)
+		a.handleApiWarnings(ref, apiWarnings)
+		if err != nil {
+			return nil, err
+		}
+		return []*uo.UnstructuredObject{o}, nil
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		pods, apiWarnings, err := a.k.ListObjects(podGvk, ref.Namespace, map[string]string{
+			"job-name": ref.Name,
+		})
+		a.handleApiWarnings(ref, apiWarnings)
+		return pods, err
+	}
+	return nil, nil
+}
+
+// getTerminationMessages returns the termination messages of all terminated containers of the given Pod
+func getTerminationMessages(pod *uo.UnstructuredObject) map[string]string {
+	ret := map[string]string{}
+	var statuses []*uo.UnstructuredObject
+	statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "initContainerStatuses")...)
+	statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "containerStatuses")...)
+	for _, cs := range statuses {
+		name, _, _ := cs.GetNestedString("name")
+		msg, _, _ := cs.GetNestedString("state", "terminated", "message")
+		if msg != "" {
+			ret[name] = msg
+		}
+	}
+	return ret
+}
+
+// collectHookLogs fetches the (truncated) logs of all containers of all Pods belonging to the given hook
+func (a *ApplyUtil) collectHookLogs(ref k8s2.ObjectRef) string {
+	pods, err := a.getHookPods(ref)
+	if err != nil {
+		return fmt.Sprintf("failed to list hook pods: %s\n", err.Error())
+	}
+
+	var buf strings.Builder
+	for _, pod := range pods {
+		var containers []*uo.UnstructuredObject
+		containers = append(containers, pod.GetNestedObjectListNoErr("spec", "initContainers")...)
+		containers = append(containers, pod.GetNestedObjectListNoErr("spec", "containers")...)
+		terminationMessages := getTerminationMessages(pod)
+
+		for _, c := range containers {
+			name, _, _ := c.GetNestedString("name")
+			buf.WriteString(fmt.Sprintf("pod/%s container %s:\n", pod.GetK8sName(), name))
+
+			logs, apiWarnings, err := a.k.GetPodLogs(pod.GetK8sNamespace(), pod.GetK8sName(), name, hookLogsTailLines, hookLogsMaxBytes*2)
+			a.handleApiWarnings(ref, apiWarnings)
+			if err != nil {
+				buf.WriteString(fmt.Sprintf("failed to retrieve logs: %s\n", err.Error()))
+				// the termination message is still available when the node is gone, and contains the tail of the logs
+				// when terminationMessagePolicy is FallbackToLogsOnError
+				msg, ok := terminationMessages[name]
+				if !ok {
+					continue
+				}
+				buf.WriteString("termination message:\n")
+				logs = msg
+			}
+			logs = truncateLogs(logs, hookLogsMaxBytes)
+			buf.WriteString(logs)
+			if logs != "" && !strings.HasSuffix(logs, "\n") {
+				buf.WriteString("\n")
+			}
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTruncateLogs(t *testing.T) {
+	assert.Equal(t, "a\nb\n", truncateLogs("a\nb\n", 10))
+
+	logs := strings.Repeat("line\n", 10)
+	truncated := truncateLogs(logs, 12)
+	assert.Equal(t, "...(truncated)\nline\nline\n", truncated)
+}
+
+func TestGetTerminationMessages(t *testing.T) {
+	pod := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p1
+status:
+  initContainerStatuses:
+  - name: init
+    state:
+      terminated:
+        exitCode: 0
+  containerStatuses:
+  - name: c1
+    state:
+      terminated:
+        exitCode: 1
+        message: "error: something failed\n"
+  - name: c2
+    state:
+      running: {}
+`)
+	assert.Equal(t, map[string]string{
+		"c1": "error: something failed\n",
+	}, getTerminationMessages(pod))
+}
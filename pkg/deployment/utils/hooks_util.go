@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
@@ -12,24 +13,32 @@ import (
 	"time"
 )
 
-var supportedKluctlHooks = []string{
+var deployHooks = []string{
 	"pre-deploy", "post-deploy",
 	"pre-deploy-initial", "post-deploy-initial",
 	"pre-deploy-upgrade", "post-deploy-upgrade",
 }
 
+var deleteHooks = []string{
+	"pre-delete", "post-delete",
+	"pre-prune", "post-prune",
+}
+
+var supportedKluctlHooks = append(append(append([]string{}, deployHooks...), deleteHooks...), "on-deploy-failure")
+
 var supportedKluctlDeletePolicies = []string{
 	"before-hook-creation",
 	"hook-succeeded",
 	"hook-failed",
 }
 
-// delete/rollback hooks are actually not supported, but we won't show warnings about that to not spam the user
+// rollback hooks are actually not supported, but we won't show warnings about that to not spam the user
 var supportedHelmHooks = []string{
 	"pre-install", "post-install",
 	"pre-upgrade", "post-upgrade",
 	"pre-delete", "post-delete",
 	"pre-rollback", "post-rollback",
+	"test", "test-success",
 }
 
 type HooksUtil struct {
@@ -41,7 +50,12 @@ func NewHooksUtil(a *ApplyUtil) *HooksUtil {
 }
 
 type hook struct {
-	object         *uo.UnstructuredObject
+	object *uo.UnstructuredObject
+
+	// external and externalItem are set for hooks configured via externalHooks in deployment.yml
+	external     *types.ExternalHookConfig
+	externalItem *deployment.DeploymentItem
+
 	hooks          map[string]bool
 	weight         int
 	deletePolicies map[string]bool
@@ -51,7 +65,7 @@ type hook struct {
 
 func (u *HooksUtil) DetermineHooks(d *deployment.DeploymentItem, hooks []string) []*hook {
 	var l []*hook
-	for _, h := range u.getSortedHooksList(d.Objects) {
+	for _, h := range u.getSortedHooksList(d) {
 		for h2 := range h.hooks {
 			if utils.FindStrInSlice(hooks, h2) != -1 {
 				l = append(l, h)
@@ -63,14 +77,20 @@ func (u *HooksUtil) DetermineHooks(d *deployment.DeploymentItem, hooks []string)
 }
 
 func (u *HooksUtil) RunHooks(hooks []*hook) {
+	u.runHooks(hooks, false)
+}
+
+// runHooks runs the given hooks. If ignoreAbort is true, the hooks are also run when the deployment got aborted, which
+// is required for the on-deploy-failure hooks.
+func (u *HooksUtil) runHooks(hooks []*hook, ignoreAbort bool) {
 	var deleteBeforeObjects []*hook
 	var applyObjects []*hook
 
 	for _, h := range hooks {
-		if u.a.abortSignal.Load().(bool) {
+		if !ignoreAbort && u.a.abortSignal.Load().(bool) {
 			return
 		}
-		if _, ok := h.deletePolicies["before-hook-creation"]; ok {
+		if _, ok := h.deletePolicies["before-hook-creation"]; ok && h.external == nil {
 			deleteBeforeObjects = append(deleteBeforeObjects, h)
 		}
 		applyObjects = append(applyObjects, h)
@@ -98,6 +118,13 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 		u.a.sctx.InfoFallback("Applying %d hooks", len(applyObjects))
 	}
 	for i, h := range applyObjects {
+		if h.external != nil {
+			u.a.sctx.UpdateAndInfoFallback("Running external hook %s (%d of %d)", h.external.Name, i+1, len(applyObjects))
+			u.runExternalHook(h)
+			u.a.sctx.Increment()
+			continue
+		}
+
 		ref := h.object.GetK8sRef()
 		_, replaced := h.deletePolicies["before-hook-creation"]
 		u.a.sctx.UpdateAndInfoFallback("Applying hook %s (%d of %d)", ref.String(), i+1, len(applyObjects))
@@ -111,12 +138,25 @@ func (u *HooksUtil) RunHooks(hooks []*hook) {
 			continue
 		}
 		waitResults[ref] = u.a.WaitReadiness(ref, h.timeout)
+
+		// logs must be collected before the hook-succeeded/hook-failed delete policies are handled
+		if !waitResults[ref] {
+			if logs := u.a.collectHookLogs(ref); logs != "" {
+				u.a.dew.SetErrorLogs(ref, logs)
+			}
+		} else if u.a.o.HookLogs {
+			if logs := u.a.collectHookLogs(ref); logs != "" {
+				u.a.mutex.Lock()
+				u.a.hookLogs[ref] = logs
+				u.a.mutex.Unlock()
+			}
+		}
 	}
 
 	var deleteAfterObjects []*hook
 	for i := len(applyObjects) - 1; i >= 0; i-- {
 		h := applyObjects[i]
-		ref := h.object.GetK8sRef()
+		ref := h.Ref()
 		waitResult, ok := waitResults[ref]
 		if !ok {
 			continue
@@ -184,8 +224,13 @@ func (u *HooksUtil) GetHook(o *uo.UnstructuredObject) *hook {
 	helmCompatibility("pre-upgrade", "pre-deploy-upgrade")
 	helmCompatibility("post-install", "post-deploy-initial")
 	helmCompatibility("post-upgrade", "post-deploy-upgrade")
+	// Helm has no concept of pruning, so we also run delete hooks when objects get pruned
 	helmCompatibility("pre-delete", "pre-delete")
+	helmCompatibility("pre-delete", "pre-prune")
 	helmCompatibility("post-delete", "post-delete")
+	helmCompatibility("post-delete", "post-prune")
+	helmCompatibility("test", "test")
+	helmCompatibility("test-success", "test")
 
 	weightStr := o.GetK8sAnnotation("kluctl.io/hook-weight")
 	if weightStr == nil {
@@ -250,21 +295,46 @@ func (u *HooksUtil) GetHook(o *uo.UnstructuredObject) *hook {
 	}
 }
 
-func (u *HooksUtil) getSortedHooksList(objects []*uo.UnstructuredObject) []*hook {
+func (u *HooksUtil) getSortedHooksList(d *deployment.DeploymentItem) []*hook {
 	var ret []*hook
-	for _, o := range objects {
+	for _, o := range d.Objects {
 		h := u.GetHook(o)
 		if h == nil {
 			continue
 		}
 		ret = append(ret, h)
 	}
+	for _, c := range d.Config.ExternalHooks {
+		h := u.getExternalHook(d, c)
+		if h == nil {
+			continue
+		}
+		ret = append(ret, h)
+	}
 	sort.SliceStable(ret, func(i, j int) bool {
 		return ret[i].weight < ret[j].weight
 	})
 	return ret
 }
 
+func (h *hook) Ref() k8s.ObjectRef {
+	if h.external != nil {
+		return externalHookRef(h.externalItem, h.external.Name)
+	}
+	return h.object.GetK8sRef()
+}
+
+// IsDeployHook returns true if the hook is applied as part of the deploy command. Hooks which are only meant for
+// other commands (e.g. Helm delete and test hooks) are not considered to be part of the deployment.
+func (h *hook) IsDeployHook() bool {
+	for x := range h.hooks {
+		if utils.FindStrInSlice(deployHooks, x) != -1 {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *hook) IsPersistent() bool {
 	for p := range h.deletePolicies {
 		if p != "before-hook-creation" && p != "hook-failed" {
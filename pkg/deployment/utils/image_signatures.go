@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/registries"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"path"
+)
+
+type imageVerificationKey struct {
+	pattern string
+	key     crypto.PublicKey
+}
+
+// VerifyImageSignatures verifies the signatures of all images that were resolved via 'images.get_image(...)'.
+// Images which don't match any of the configured key patterns are not verified. Verification failures are
+// reported as errors for the objects that use the image.
+func VerifyImageSignatures(ctx context.Context, dew *DeploymentErrorsAndWarnings, images *deployment.Images, keys []types.ImageVerificationKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var parsedKeys []imageVerificationKey
+	for _, k := range keys {
+		if k.PublicKey == nil {
+			return fmt.Errorf("missing public key for image pattern %s", k.Pattern)
+		}
+		key, err := registries.ParsePublicKey([]byte(*k.PublicKey))
+		if err != nil {
+			return fmt.Errorf("invalid public key for image pattern %s: %w", k.Pattern, err)
+		}
+		parsedKeys = append(parsedKeys, imageVerificationKey{pattern: k.Pattern, key: key})
+	}
+
+	seenImages := images.SeenImages(false)
+
+	s := status.Start(ctx, "Verifying image signatures")
+	defer s.Failed()
+
+	results := map[string]error{}
+	failed := 0
+	for _, si := range seenImages {
+		// verify the image that actually gets deployed
+		image := si.ResultImage
+		if si.RewrittenImage != nil {
+			image = *si.RewrittenImage
+		}
+		if image == "" {
+			continue
+		}
+
+		repo := deployment.ImageRepository(image)
+		var matchingKeys []crypto.PublicKey
+		for _, k := range parsedKeys {
+			m, err := path.Match(k.pattern, repo)
+			if err != nil {
+				return fmt.Errorf("invalid image pattern %s: %w", k.pattern, err)
+			}
+			if m {
+				matchingKeys = append(matchingKeys, k.key)
+			}
+		}
+		if len(matchingKeys) == 0 {
+			continue
+		}
+
+		err, ok := results[image]
+		if !ok {
+			err = images.VerifyImageSignature(image, matchingKeys)
+			results[image] = err
+		}
+		if err != nil {
+			var ref k8s2.ObjectRef
+			if si.Object != nil {
+				ref = *si.Object
+			}
+			dew.AddError(ref, fmt.Errorf("signature verification for image %s failed: %w", image, err))
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		s.Update("Signature verification failed for %d images", failed)
+		return nil
+	}
+
+	s.UpdateAndInfoFallback("Verified signatures of %d images", len(results))
+	s.Success()
+	return nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+)
+
+// immutableFields is a list of well known fields that can not be changed after creation. Changing these requires the
+// object to be deleted and re-created.
+var immutableFields = map[schema.GroupKind][]string{
+	{Group: "batch", Kind: "Job"}:                                    {"spec.selector", "spec.template"},
+	{Group: "apps", Kind: "Deployment"}:                              {"spec.selector"},
+	{Group: "apps", Kind: "ReplicaSet"}:                              {"spec.selector"},
+	{Group: "apps", Kind: "DaemonSet"}:                               {"spec.selector"},
+	{Group: "apps", Kind: "StatefulSet"}:                             {"spec.selector", "spec.serviceName", "spec.podManagementPolicy", "spec.volumeClaimTemplates"},
+	{Group: "", Kind: "Service"}:                                     {"spec.clusterIP", "spec.clusterIPs"},
+	{Group: "", Kind: "PersistentVolumeClaim"}:                       {"spec.storageClassName", "spec.volumeName", "spec.accessModes", "spec.selector", "spec.volumeMode"},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                  {"provisioner", "parameters", "reclaimPolicy", "volumeBindingMode"},
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        {"roleRef"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: {"roleRef"},
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:               {"spec.controller"},
+}
+
+func isPathOrChild(p string, parent string) bool {
+	if !strings.HasPrefix(p, parent) {
+		return false
+	}
+	if len(p) == len(parent) {
+		return true
+	}
+	return p[len(parent)] == '.' || p[len(parent)] == '['
+}
+
+// getImmutableChanges returns the paths of all changes that touch well known immutable fields
+func getImmutableChanges(gk schema.GroupKind, changes []types.Change) []string {
+	fields, ok := immutableFields[gk]
+	if !ok {
+		return nil
+	}
+	var ret []string
+	for _, c := range changes {
+		for _, f := range fields {
+			if isPathOrChild(c.JsonPath, f) {
+				ret = append(ret, c.JsonPath)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// handleImmutableFieldError is called when applying an object failed due to changes to immutable fields. In dry-run
+// mode, the object is remembered as requiring recreation and the recreation is simulated. Otherwise, the object is
+// recreated if this is allowed via the kluctl.io/apply-strategy annotation or was confirmed before. Returns false if
+// the error was not handled.
+func (a *ApplyUtil) handleImmutableFieldError(x *uo.UnstructuredObject, hook bool) bool {
+	ref := x.GetK8sRef()
+
+	if hasApplyStrategy(x, applyStrategyRecreateOnImmutableChange) || a.o.RecreateObjects[ref] {
+		warn := fmt.Errorf("%s contains changes to immutable fields, recreating it", ref.String())
+		a.HandleWarning(ref, warn)
+		status.Warning(a.ctx, warn.Error())
+		a.recreateObject(x, hook)
+		return true
+	}
+
+	if a.o.DryRun {
+		a.mutex.Lock()
+		a.requireRecreation[ref] = true
+		a.mutex.Unlock()
+
+		// simulate re-creation so that the diff shows the resulting object
+		a.ApplyObject(x, true, hook)
+		return true
+	}
+	return false
+}
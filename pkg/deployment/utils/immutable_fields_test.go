@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+)
+
+func TestGetImmutableChanges(t *testing.T) {
+	changes := []types.Change{
+		{JsonPath: "spec.replicas"},
+		{JsonPath: "spec.selector.matchLabels.app"},
+		{JsonPath: "spec.selectorX"},
+	}
+
+	assert.Equal(t, []string{"spec.selector.matchLabels.app"}, getImmutableChanges(schema.GroupKind{Group: "apps", Kind: "Deployment"}, changes))
+	assert.Empty(t, getImmutableChanges(schema.GroupKind{Kind: "ConfigMap"}, changes))
+
+	changes = []types.Change{
+		{JsonPath: "spec.template.spec.containers[name=job].image"},
+	}
+	assert.Equal(t, []string{"spec.template.spec.containers[name=job].image"}, getImmutableChanges(schema.GroupKind{Group: "batch", Kind: "Job"}, changes))
+}
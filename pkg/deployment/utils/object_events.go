@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxObjectEvents is the maximum number of (deduplicated) events returned by CollectObjectEvents
+const maxObjectEvents = 10
+
+var (
+	podGvk        = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	replicaSetGvk = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}
+)
+
+// ignoredContainerWaitingReasons are reasons of waiting containers which do not indicate a problem
+var ignoredContainerWaitingReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+type objectEvent struct {
+	ref     k8s2.ObjectRef
+	reason  string
+	message string
+	count   int32
+	time    time.Time
+
+	// otherObjects is the number of other objects that reported the same event
+	otherObjects int
+}
+
+func (e *objectEvent) String() string {
+	s := fmt.Sprintf("%s/%s", e.ref.GVK.Kind, e.ref.Name)
+	if e.otherObjects != 0 {
+		s += fmt.Sprintf(" (and %d more)", e.otherObjects)
+	}
+	s += fmt.Sprintf(": %s: %s", e.reason, e.message)
+	if e.count > 1 {
+		s += fmt.Sprintf(" (x%d)", e.count)
+	}
+	return s
+}
+
+// getOwnedObjects returns the ReplicaSets and Pods that are (directly or indirectly) owned by the given object. The
+// object's selector is used to limit the listed objects.
+func getOwnedObjects(k *k8s.K8sCluster, o *uo.UnstructuredObject) ([]*uo.UnstructuredObject, error) {
+	matchLabels, ok, _ := o.GetNestedStringMapCopy("spec", "selector", "matchLabels")
+	if !ok || len(matchLabels) == 0 {
+		return nil, nil
+	}
+
+	owners := map[string]bool{
+		o.GetK8sUid(): true,
+	}
+	isOwned := func(x *uo.UnstructuredObject) bool {
+		for _, ref := range x.GetK8sOwnerReferences() {
+			uid, _, _ := ref.GetNestedString("uid")
+			if owners[uid] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var ret []*uo.UnstructuredObject
+	if o.GetK8sGVK().GroupKind() == (schema.GroupKind{Group: "apps", Kind: "Deployment"}) {
+		rsList, _, err := k.ListObjects(replicaSetGvk, o.GetK8sNamespace(), matchLabels)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		for _, rs := range rsList {
+			if isOwned(rs) {
+				owners[rs.GetK8sUid()] = true
+				ret = append(ret, rs)
+			}
+		}
+	}
+
+	pods, _, err := k.ListObjects(podGvk, o.GetK8sNamespace(), matchLabels)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if isOwned(pod) {
+			ret = append(ret, pod)
+		}
+	}
+	return ret, nil
+}
+
+// getContainerProblems returns pseudo events for containers that are waiting for problematic reasons (e.g.
+// CrashLoopBackOff or ImagePullBackOff) or were terminated due to being OOM killed, as these are not always visible in
+// events
+func getContainerProblems(pod *uo.UnstructuredObject) []*objectEvent {
+	var ret []*objectEvent
+
+	var statuses []*uo.UnstructuredObject
+	statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "initContainerStatuses")...)
+	statuses = append(statuses, pod.GetNestedObjectListNoErr("status", "containerStatuses")...)
+
+	for _, cs := range statuses {
+		name, _, _ := cs.GetNestedString("name")
+		restartCount, _, _ := cs.GetNestedInt("restartCount")
+
+		if reason, ok, _ := cs.GetNestedString("state", "waiting", "reason"); ok && !ignoredContainerWaitingReasons[reason] {
+			message, _, _ := cs.GetNestedString("state", "waiting", "message")
+			if message == "" {
+				message = fmt.Sprintf("container %s is waiting", name)
+			}
+			ret = append(ret, &objectEvent{
+				ref:     pod.GetK8sRef(),
+				reason:  reason,
+				message: message,
+				count:   1,
+				time:    time.Now(),
+			})
+		}
+		for _, state := range []string{"state", "lastState"} {
+			reason, _, _ := cs.GetNestedString(state, "terminated", "reason")
+			if reason != "OOMKilled" {
+				continue
+			}
+			ret = append(ret, &objectEvent{
+				ref:     pod.GetK8sRef(),
+				reason:  reason,
+				message: fmt.Sprintf("container %s was killed due to running out of memory (%d restarts)", name, restartCount),
+				count:   1,
+				time:    time.Now(),
+			})
+			break
+		}
+	}
+	return ret
+}
+
+// dedupObjectEvents merges events with the same reason and message, e.g. reported by multiple Pods of the same
+// ReplicaSet. Only the most recent maxObjectEvents events are returned, sorted by time.
+func dedupObjectEvents(events []*objectEvent) []*objectEvent {
+	type key struct {
+		reason  string
+		message string
+	}
+	byKey := map[key]*objectEvent{}
+	var ret []*objectEvent
+	for _, e := range events {
+		k := key{reason: e.reason, message: e.message}
+		e2, ok := byKey[k]
+		if !ok {
+			e2 = &objectEvent{}
+			*e2 = *e
+			byKey[k] = e2
+			ret = append(ret, e2)
+			continue
+		}
+		if e2.ref != e.ref {
+			e2.otherObjects++
+		}
+		e2.count += e.count
+		if e.time.After(e2.time) {
+			e2.time = e.time
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].time.Before(ret[j].time)
+	})
+	if len(ret) > maxObjectEvents {
+		ret = ret[len(ret)-maxObjectEvents:]
+	}
+	return ret
+}
+
+// CollectObjectEvents returns recent warning events of the given object and the ReplicaSets and Pods owned by it,
+// including problems of Pod containers like OOM kills. Events with the same reason and message are deduplicated.
+func CollectObjectEvents(k *k8s.K8sCluster, ref k8s2.ObjectRef) ([]string, error) {
+	refs := []k8s2.ObjectRef{ref}
+	var events []*objectEvent
+
+	o, _, err := k.GetSingleObject(ref)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if o != nil {
+		owned, err := getOwnedObjects(k, o)
+		if err != nil {
+			return nil, err
+		}
+		if ref.GVK.GroupKind() == podGvk.GroupKind() {
+			owned = append(owned, o)
+		}
+		for _, x := range owned {
+			if x.GetK8sRef() != ref {
+				refs = append(refs, x.GetK8sRef())
+			}
+			if x.GetK8sGVK().GroupKind() == podGvk.GroupKind() {
+				events = append(events, getContainerProblems(x)...)
+			}
+		}
+	}
+
+	for _, ref2 := range refs {
+		l, _, err := k.ListEvents(ref2)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range l {
+			if e.Type != corev1.EventTypeWarning {
+				continue
+			}
+			count := e.Count
+			if e.Series != nil {
+				count = e.Series.Count
+			}
+			events = append(events, &objectEvent{
+				ref:     ref2,
+				reason:  e.Reason,
+				message: e.Message,
+				count:   count,
+				time:    k8s.GetEventTime(e),
+			})
+		}
+	}
+
+	var ret []string
+	for _, e := range dedupObjectEvents(events) {
+		ret = append(ret, e.String())
+	}
+	return ret, nil
+}
+
+// collectObjectEvents collects the events of the given object and attaches them to all errors of the object
+func (a *ApplyUtil) collectObjectEvents(ref k8s2.ObjectRef) {
+	events, err := CollectObjectEvents(a.k, ref)
+	if err != nil {
+		a.HandleWarning(ref, fmt.Errorf("failed to collect events: %w", err))
+		return
+	}
+	if len(events) != 0 {
+		a.dew.SetErrorEvents(ref, strings.Join(events, "\n"))
+	}
+}
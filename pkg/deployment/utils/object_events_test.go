@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+	"time"
+)
+
+func TestDedupObjectEvents(t *testing.T) {
+	now := time.Now()
+	buildRef := func(name string) k8s2.ObjectRef {
+		return k8s2.ObjectRef{GVK: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, Name: name, Namespace: "default"}
+	}
+
+	events := dedupObjectEvents([]*objectEvent{
+		{ref: buildRef("p1"), reason: "BackOff", message: "Back-off pulling image", count: 2, time: now.Add(-time.Minute)},
+		{ref: buildRef("p2"), reason: "FailedScheduling", message: "0/3 nodes are available", count: 1, time: now.Add(-time.Hour)},
+		{ref: buildRef("p2"), reason: "BackOff", message: "Back-off pulling image", count: 3, time: now},
+		{ref: buildRef("p3"), reason: "BackOff", message: "Back-off pulling image", count: 1, time: now.Add(-time.Second)},
+	})
+
+	var s []string
+	for _, e := range events {
+		s = append(s, e.String())
+	}
+	assert.Equal(t, []string{
+		"Pod/p2: FailedScheduling: 0/3 nodes are available",
+		"Pod/p1 (and 2 more): BackOff: Back-off pulling image (x6)",
+	}, s)
+}
+
+func TestGetContainerProblems(t *testing.T) {
+	pod := uo.FromStringMust(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p1
+  namespace: default
+status:
+  containerStatuses:
+  - name: app
+    restartCount: 3
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+        message: back-off 40s restarting failed container
+    lastState:
+      terminated:
+        reason: OOMKilled
+  - name: sidecar
+    state:
+      waiting:
+        reason: ContainerCreating
+`)
+
+	var s []string
+	for _, e := range getContainerProblems(pod) {
+		s = append(s, e.String())
+	}
+	assert.Equal(t, []string{
+		"Pod/p1: CrashLoopBackOff: back-off 40s restarting failed container",
+		"Pod/p1: OOMKilled: container app was killed due to running out of memory (3 restarts)",
+	}, s)
+}
+
+func TestCollectObjectEvents(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "p1", Namespace: "default"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image \"app:1\"",
+				}},
+			}},
+		},
+	}
+	buildEvent := func(name string, typ string, reason string, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     v1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "p1", Namespace: "default"},
+			Type:           typ,
+			Reason:         reason,
+			Message:        message,
+			Count:          2,
+			LastTimestamp:  v1.NewTime(time.Now().Add(-time.Minute)),
+		}
+	}
+
+	k, err := k8s.NewK8sCluster(context.TODO(), k8s.NewFakeClientFactory(
+		pod,
+		buildEvent("e1", corev1.EventTypeNormal, "Pulling", "Pulling image \"app:1\""),
+		buildEvent("e2", corev1.EventTypeWarning, "Failed", "Failed to pull image \"app:1\""),
+	), false)
+	assert.NoError(t, err)
+
+	events, err := CollectObjectEvents(k, k8s2.ObjectRef{
+		GVK:       schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Name:      "p1",
+		Namespace: "default",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Pod/p1: Failed: Failed to pull image \"app:1\" (x2)",
+		"Pod/p1: ImagePullBackOff: Back-off pulling image \"app:1\"",
+	}, events)
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+	"time"
+)
+
+// podTemplateSpecPaths are the paths at which workload objects store the spec of the Pods they create
+var podTemplateSpecPaths = [][]interface{}{
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// GetLastApplied returns the most recent time at which kluctl applied the given object, as recorded in its
+// managedFields. Returns nil if kluctl never applied the object.
+func GetLastApplied(o *uo.UnstructuredObject) *time.Time {
+	var ret *time.Time
+	for _, mf := range o.GetK8sManagedFields() {
+		mgr, _, _ := mf.GetNestedString("manager")
+		if mgr != "kluctl" {
+			continue
+		}
+		s, ok, _ := mf.GetNestedString("time")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			continue
+		}
+		if ret == nil || t.After(*ret) {
+			ret = &t
+		}
+	}
+	return ret
+}
+
+func getContainerImages(podSpec *uo.UnstructuredObject) []string {
+	var ret []string
+	for _, field := range []string{"initContainers", "containers"} {
+		for _, c := range podSpec.GetNestedObjectListNoErr(field) {
+			image, ok, _ := c.GetNestedString("image")
+			if ok && image != "" {
+				ret = append(ret, image)
+			}
+		}
+	}
+	return ret
+}
+
+// getPodTemplateImages returns the images found in the Pod template of the given workload object
+func getPodTemplateImages(o *uo.UnstructuredObject) []string {
+	for _, p := range podTemplateSpecPaths {
+		podSpec, ok, _ := o.GetNestedObject(p...)
+		if ok {
+			return getContainerImages(podSpec)
+		}
+	}
+	return nil
+}
+
+// getPodImages returns the images that are actually running in the given Pod, as reported by its container statuses.
+// The images from the Pod spec are used if the Pod has no container statuses yet.
+func getPodImages(pod *uo.UnstructuredObject) []string {
+	var ret []string
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		for _, cs := range pod.GetNestedObjectListNoErr("status", field) {
+			image, ok, _ := cs.GetNestedString("image")
+			if ok && image != "" {
+				ret = append(ret, image)
+			}
+		}
+	}
+	if len(ret) != 0 {
+		return ret
+	}
+	podSpec, ok, _ := pod.GetNestedObject("spec")
+	if !ok {
+		return nil
+	}
+	return getContainerImages(podSpec)
+}
+
+func sortedUniqueImages(images []string) []string {
+	m := map[string]bool{}
+	var ret []string
+	for _, image := range images {
+		if !m[image] {
+			m[image] = true
+			ret = append(ret, image)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// GetRunningImages returns the sorted list of images that are currently running for the given object. For Pods, the
+// container statuses are used. For workloads (e.g. Deployments or StatefulSets), the images of the owned Pods are
+// used. If no owned Pods exist (e.g. because the workload is scaled down), the images of the Pod template are
+// returned. Returns nil for objects that do not run any containers.
+func GetRunningImages(k *k8s.K8sCluster, o *uo.UnstructuredObject) ([]string, error) {
+	if o.GetK8sGVK().GroupKind() == podGvk.GroupKind() {
+		return sortedUniqueImages(getPodImages(o)), nil
+	}
+
+	templateImages := getPodTemplateImages(o)
+	if len(templateImages) == 0 {
+		return nil, nil
+	}
+
+	owned, err := getOwnedObjects(k, o)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, x := range owned {
+		if x.GetK8sGVK().GroupKind() == podGvk.GroupKind() {
+			images = append(images, getPodImages(x)...)
+		}
+	}
+	if len(images) == 0 {
+		images = templateImages
+	}
+	return sortedUniqueImages(images), nil
+}
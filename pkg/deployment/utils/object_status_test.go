@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestGetLastApplied(t *testing.T) {
+	o := uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  managedFields:
+  - manager: kluctl
+    operation: Apply
+    time: "2023-01-01T10:00:00Z"
+  - manager: kubectl
+    operation: Update
+    time: "2023-03-01T10:00:00Z"
+  - manager: kluctl
+    operation: Apply
+    time: "2023-02-01T10:00:00Z"
+`)
+	lastApplied := GetLastApplied(o)
+	assert.NotNil(t, lastApplied)
+	assert.Equal(t, time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC), lastApplied.UTC())
+
+	o = uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+`)
+	assert.Nil(t, GetLastApplied(o))
+}
+
+func TestGetPodTemplateImages(t *testing.T) {
+	cronJob := uo.FromStringMust(`
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cj
+  namespace: default
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: init
+            image: init:1
+          containers:
+          - name: app
+            image: app:1
+`)
+	assert.Equal(t, []string{"init:1", "app:1"}, getPodTemplateImages(cronJob))
+
+	cm := uo.FromStringMust(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+`)
+	assert.Nil(t, getPodTemplateImages(cm))
+}
+
+func TestGetRunningImages(t *testing.T) {
+	buildSts := func(name string) *uo.UnstructuredObject {
+		return uo.FromStringMust(fmt.Sprintf(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: %[1]s
+  namespace: default
+  uid: uid-%[1]s
+spec:
+  selector:
+    matchLabels:
+      app: %[1]s
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2
+`, name))
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "s1-0",
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "s1"},
+			OwnerReferences: []v1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "s1", UID: "uid-s1"}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:2"}}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Image: "docker.io/library/app:1"}},
+		},
+	}
+
+	k, err := k8s.NewK8sCluster(context.TODO(), k8s.NewFakeClientFactory(pod), false)
+	assert.NoError(t, err)
+
+	// the running Pod still uses the old image
+	images, err := GetRunningImages(k, buildSts("s1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docker.io/library/app:1"}, images)
+
+	// no Pods exist, so the template is used
+	images, err = GetRunningImages(k, buildSts("s2"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app:2"}, images)
+
+	podObject, _, err := k.GetSingleObject(k8s2.ObjectRef{GVK: podGvk, Name: "s1-0", Namespace: "default"})
+	assert.NoError(t, err)
+	images, err = GetRunningImages(k, podObject)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docker.io/library/app:1"}, images)
+}
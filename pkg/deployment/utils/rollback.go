@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sort"
+)
+
+// rollbackAppliedObjects restores all objects applied by this ApplyUtil to the state captured by
+// RemoteObjectUtils.UpdateRemoteObjects before deployment started. Objects which did not exist before are deleted.
+func (a *ApplyUtil) rollbackAppliedObjects() {
+	if a.o.DryRun {
+		return
+	}
+
+	a.mutex.Lock()
+	var refs []k8s2.ObjectRef
+	for ref := range a.appliedObjects {
+		if _, ok := a.appliedHookObjects[ref]; ok {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	a.mutex.Unlock()
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+
+	a.sctx.InfoFallback("Rolling back %d objects", len(refs))
+
+	for _, ref := range refs {
+		remoteObject := a.ru.GetRemoteObject(ref)
+		if remoteObject == nil {
+			apiWarnings, err := a.k.DeleteSingleObject(ref, k8s.DeleteOptions{})
+			a.handleApiWarnings(ref, apiWarnings)
+			if err != nil && !errors.IsNotFound(err) {
+				a.HandleError(ref, fmt.Errorf("failed to roll back %s: %w", ref.String(), err))
+				continue
+			}
+
+			a.mutex.Lock()
+			delete(a.appliedObjects, ref)
+			a.rolledBackObjects = append(a.rolledBackObjects, types.RolledBackObject{Ref: ref, Action: "deleted"})
+			a.mutex.Unlock()
+			continue
+		}
+
+		errorCount := a.errorCount
+		a.ReplaceObject(ref, nil, func(o *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+			x := remoteObject.Clone()
+			x.SetK8sResourceVersion(o.GetK8sResourceVersion())
+			_ = x.RemoveNestedField("metadata", "managedFields")
+			return x, nil
+		})
+		if a.errorCount != errorCount {
+			continue
+		}
+
+		// restored objects are not reported as applied, as the applied state is not present in the cluster anymore
+		a.mutex.Lock()
+		delete(a.appliedObjects, ref)
+		a.rolledBackObjects = append(a.rolledBackObjects, types.RolledBackObject{Ref: ref, Action: "restored"})
+		a.mutex.Unlock()
+	}
+}
+
+func (ad *ApplyDeploymentsUtil) GetRolledBackObjects() []types.RolledBackObject {
+	ad.resultsMutex.Lock()
+	defer ad.resultsMutex.Unlock()
+
+	var ret []types.RolledBackObject
+	for _, a := range ad.results {
+		ret = append(ret, a.rolledBackObjects...)
+	}
+	return ret
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var defaultRolloutSteps = []int{25, 50}
+
+const rolloutCanaryLabel = "kluctl.io/rollout-canary"
+
+// getRolloutSteps returns the rollout steps for the given object, either from the 'kluctl.io/rollout-steps'
+// annotation or from the 'rollout' config of the deployment item. Returns nil if no progressive rollout is requested.
+func getRolloutSteps(d *deployment.DeploymentItem, o *uo.UnstructuredObject) ([]int, error) {
+	a := o.GetK8sAnnotation("kluctl.io/rollout-steps")
+	if a != nil {
+		var steps []int
+		for _, s := range strings.Split(*a, ",") {
+			p, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+			if err != nil || p <= 0 || p > 100 || (len(steps) != 0 && int(p) <= steps[len(steps)-1]) {
+				return nil, fmt.Errorf("invalid kluctl.io/rollout-steps annotation '%s', expected ascending percentages between 1 and 100", *a)
+			}
+			steps = append(steps, int(p))
+		}
+		return steps, nil
+	}
+	if d.Config.Rollout != nil {
+		if len(d.Config.Rollout.Steps) == 0 {
+			return defaultRolloutSteps, nil
+		}
+		return d.Config.Rollout.Steps, nil
+	}
+	return nil, nil
+}
+
+// rolloutReplicas converts the percentage based steps into replica counts, omitting all steps that would already
+// update all replicas.
+func rolloutReplicas(replicas int64, steps []int) []int64 {
+	var ret []int64
+	for _, p := range steps {
+		n := (replicas*int64(p) + 99) / 100
+		if n >= replicas {
+			break
+		}
+		if len(ret) != 0 && ret[len(ret)-1] == n {
+			continue
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// needsRollout performs a dry-run apply to figure out if the pod template would change
+func (a *ApplyUtil) needsRollout(x *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject) bool {
+	r, _, err := a.k.PatchObject(x, k8s.PatchOptions{ForceDryRun: true})
+	if err != nil {
+		// let the normal apply handle the error
+		return false
+	}
+	t1, _, _ := r.GetNestedField("spec", "template")
+	t2, _, _ := remoteObject.GetNestedField("spec", "template")
+	return !reflect.DeepEqual(t1, t2)
+}
+
+// applyWithRollout applies StatefulSets and Deployments in multiple steps and waits for readiness after each step.
+// If any step fails, the rollout is aborted and the object is reverted to its previous spec. Returns false if the
+// object is not eligible for a progressive rollout, in which case it must be applied the normal way.
+func (a *ApplyUtil) applyWithRollout(x *uo.UnstructuredObject, steps []int) bool {
+	if a.o.DryRun || a.o.NoWait {
+		return false
+	}
+	if strategy, _ := getApplyStrategy(x); strategy != applyStrategyDefault {
+		// objects with custom apply strategies are always applied the normal way
+		return false
+	}
+
+	ref := x.GetK8sRef()
+	remoteObject := a.ru.GetRemoteObject(ref)
+	if remoteObject == nil {
+		// initial deployments are not rolled out progressively
+		return false
+	}
+
+	replicas, ok, _ := x.GetNestedInt("spec", "replicas")
+	if !ok {
+		replicas, ok, _ = remoteObject.GetNestedInt("spec", "replicas")
+		if !ok {
+			replicas = 1
+		}
+	}
+	stepReplicas := rolloutReplicas(replicas, steps)
+	if len(stepReplicas) == 0 {
+		return false
+	}
+
+	switch ref.GVK.GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		strategy, _, _ := remoteObject.GetNestedString("spec", "updateStrategy", "type")
+		if strategy != "" && strategy != "RollingUpdate" {
+			return false
+		}
+		if !a.needsRollout(x, remoteObject) {
+			return false
+		}
+		a.rolloutStatefulSet(x, remoteObject, replicas, stepReplicas)
+		return true
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		if !a.needsRollout(x, remoteObject) {
+			return false
+		}
+		a.rolloutDeployment(x, remoteObject, replicas, stepReplicas)
+		return true
+	}
+	return false
+}
+
+func (a *ApplyUtil) rolloutStatefulSet(x *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject, replicas int64, stepReplicas []int64) {
+	ref := x.GetK8sRef()
+	totalSteps := len(stepReplicas) + 1
+
+	for i, n := range stepReplicas {
+		a.sctx.InfoFallback("Rolling out %s, step %d of %d (%d of %d replicas)", ref.String(), i+1, totalSteps, n, replicas)
+
+		x2 := x.Clone()
+		_ = x2.SetNestedField(replicas-n, "spec", "updateStrategy", "rollingUpdate", "partition")
+		a.ApplyObject(x2, false, false)
+		if a.HadError(ref) || !a.WaitReadiness(ref, 0) {
+			a.revertRollout(ref, remoteObject, i+1)
+			return
+		}
+	}
+
+	a.sctx.InfoFallback("Rolling out %s, step %d of %d (%d of %d replicas)", ref.String(), totalSteps, totalSteps, replicas, replicas)
+	a.ApplyObject(x, false, false)
+	if a.HadError(ref) || !a.WaitReadiness(ref, 0) {
+		a.revertRollout(ref, remoteObject, totalSteps)
+	}
+}
+
+// buildRolloutCanary builds the canary Deployment used while rolling out the given Deployment. The canary gets an
+// additional label in its selector and pod template, so that it never selects the Pods of the original Deployment.
+func buildRolloutCanary(x *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+	ref := x.GetK8sRef()
+
+	canary := x.Clone()
+	canary.SetK8sName(fmt.Sprintf("%s-rollout-canary", ref.Name))
+	canary.SetK8sAnnotation("kluctl.io/rollout-canary-of", ref.Name)
+	canary.SetK8sLabel(rolloutCanaryLabel, ref.Name)
+
+	err := canary.SetNestedField(ref.Name, "spec", "selector", "matchLabels", rolloutCanaryLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rollout canary for %s: %w", ref.String(), err)
+	}
+	err = canary.SetNestedField(ref.Name, "spec", "template", "metadata", "labels", rolloutCanaryLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rollout canary for %s: %w", ref.String(), err)
+	}
+	return canary, nil
+}
+
+func (a *ApplyUtil) rolloutDeployment(x *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject, replicas int64, stepReplicas []int64) {
+	ref := x.GetK8sRef()
+	totalSteps := len(stepReplicas) + 1
+
+	remoteReplicas, ok, _ := remoteObject.GetNestedInt("spec", "replicas")
+	if !ok {
+		remoteReplicas = 1
+	}
+
+	// the canary deployment runs the new pod template with a subset of the replicas, while the original deployment
+	// is scaled down accordingly
+	canary, err := buildRolloutCanary(x)
+	if err != nil {
+		a.HandleError(ref, err)
+		return
+	}
+	canaryRef := canary.GetK8sRef()
+
+	deleteCanary := func() {
+		apiWarnings, err := a.k.DeleteSingleObject(canaryRef, k8s.DeleteOptions{})
+		a.handleApiWarnings(canaryRef, apiWarnings)
+		if err != nil && !errors.IsNotFound(err) {
+			a.HandleError(canaryRef, err)
+		}
+	}
+	scaleOriginal := func(n int64) {
+		a.ReplaceObject(ref, nil, func(o *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+			err := o.SetNestedField(n, "spec", "replicas")
+			return o, err
+		})
+	}
+
+	for i, n := range stepReplicas {
+		a.sctx.InfoFallback("Rolling out %s, step %d of %d (%d of %d replicas)", ref.String(), i+1, totalSteps, n, replicas)
+
+		_ = canary.SetNestedField(n, "spec", "replicas")
+		_, apiWarnings, err := a.k.PatchObject(canary, k8s.PatchOptions{ForceApply: true})
+		a.handleApiWarnings(canaryRef, apiWarnings)
+		if err != nil {
+			a.HandleError(ref, fmt.Errorf("failed to apply rollout canary %s: %w", canaryRef.String(), err))
+			deleteCanary()
+			a.revertRollout(ref, remoteObject, i+1)
+			return
+		}
+		if !a.WaitReadiness(canaryRef, 0) {
+			deleteCanary()
+			a.revertRollout(ref, remoteObject, i+1)
+			return
+		}
+		scaleOriginal(remoteReplicas - n)
+	}
+
+	a.sctx.InfoFallback("Rolling out %s, step %d of %d (%d of %d replicas)", ref.String(), totalSteps, totalSteps, replicas, replicas)
+	a.ApplyObject(x, false, false)
+	if !a.HadError(ref) {
+		if _, ok, _ := x.GetNestedInt("spec", "replicas"); !ok {
+			// replicas are not managed by us (e.g. because of a HPA), so we must restore the original value
+			scaleOriginal(remoteReplicas)
+		}
+	}
+	ok = !a.HadError(ref) && a.WaitReadiness(ref, 0)
+	deleteCanary()
+	if !ok {
+		a.revertRollout(ref, remoteObject, totalSteps)
+	}
+}
+
+// revertRollout restores the spec of the object as it was before the rollout started
+func (a *ApplyUtil) revertRollout(ref k8s2.ObjectRef, remoteObject *uo.UnstructuredObject, step int) {
+	a.sctx.InfoFallback("Rollout of %s failed in step %d, reverting to previous spec", ref.String(), step)
+
+	a.ReplaceObject(ref, nil, func(o *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+		spec, _, _ := remoteObject.Clone().GetNestedField("spec")
+		err := o.SetNestedField(spec, "spec")
+		return o, err
+	})
+	a.HandleWarning(ref, fmt.Errorf("rollout of %s was aborted in step %d and reverted to the previous spec", ref.String(), step))
+}
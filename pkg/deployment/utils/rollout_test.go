@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRolloutReplicas(t *testing.T) {
+	assert.Equal(t, []int64{1, 2}, rolloutReplicas(4, []int{25, 50}))
+	assert.Equal(t, []int64{1, 2, 3}, rolloutReplicas(10, []int{10, 20, 25}))
+	assert.Equal(t, []int64{1}, rolloutReplicas(3, []int{10, 20}))
+	assert.Nil(t, rolloutReplicas(1, []int{25, 50}))
+	assert.Nil(t, rolloutReplicas(4, []int{100}))
+}
+
+func TestBuildRolloutCanary(t *testing.T) {
+	x := uo.FromStringMust(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+  labels:
+    app: app
+spec:
+  replicas: 4
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: app:2
+`)
+	canary, err := buildRolloutCanary(x)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "app-rollout-canary", canary.GetK8sName())
+	assert.Equal(t, "app", *canary.GetK8sAnnotation("kluctl.io/rollout-canary-of"))
+
+	expectedLabels := map[string]interface{}{
+		"app":                      "app",
+		"kluctl.io/rollout-canary": "app",
+	}
+	selector, _, _ := canary.GetNestedField("spec", "selector", "matchLabels")
+	assert.Equal(t, expectedLabels, selector)
+	podLabels, _, _ := canary.GetNestedField("spec", "template", "metadata", "labels")
+	assert.Equal(t, expectedLabels, podLabels)
+
+	// the original object must not be modified
+	selector, _, _ = x.GetNestedField("spec", "selector", "matchLabels")
+	assert.Equal(t, map[string]interface{}{"app": "app"}, selector)
+}
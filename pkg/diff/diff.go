@@ -35,7 +35,7 @@ func convertPath(path []string, o interface{}) (string, error) {
 		ret = append(ret, p)
 		o = x
 	}
-	return ret.ToJsonPath(), nil
+	return formatChangePath(ret), nil
 }
 
 func Diff(oldObject *uo.UnstructuredObject, newObject *uo.UnstructuredObject) ([]types.Change, error) {
@@ -43,14 +43,21 @@ func Diff(oldObject *uo.UnstructuredObject, newObject *uo.UnstructuredObject) ([
 	if err != nil {
 		return nil, err
 	}
-	cl, err := differ.Diff(oldObject.Object, newObject.Object)
+
+	// lists with known merge keys (e.g. containers or env) are matched by key instead of index
+	oldKeyed := uo.FromMap(keyListsByMergeKeys(oldObject.Object).(map[string]interface{}))
+	newKeyed := uo.FromMap(keyListsByMergeKeys(newObject.Object).(map[string]interface{}))
+
+	cl, err := differ.Diff(oldKeyed.Object, newKeyed.Object)
 	if err != nil {
 		return nil, err
 	}
 
 	var changes []types.Change
 	for _, c := range cl {
-		c2, err := convertChange(c, oldObject, newObject)
+		c.From = restoreMergeKeyLists(c.From)
+		c.To = restoreMergeKeyLists(c.To)
+		c2, err := convertChange(c, oldKeyed, newKeyed)
 		if err != nil {
 			return nil, err
 		}
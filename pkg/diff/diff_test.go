@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffMergeKeys(t *testing.T) {
+	oldObject := uo.FromStringMust(`
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:1
+      - name: app
+        image: app:1
+        env:
+        - name: A
+          value: a
+        - name: B
+          value: b
+        ports:
+        - containerPort: 80
+`)
+	newObject := uo.FromStringMust(`
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2
+        env:
+        - name: B
+          value: b
+        - name: A
+          value: a2
+        ports:
+        - containerPort: 80
+        - containerPort: 443
+      - name: sidecar
+        image: sidecar:1
+`)
+
+	changes, err := Diff(oldObject, newObject)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.JsonPath)
+	}
+	assert.ElementsMatch(t, []string{
+		"spec.template.spec.containers[name=app].image",
+		"spec.template.spec.containers[name=app].env[name=A].value",
+		"spec.template.spec.containers[name=app].ports[containerPort=443]",
+	}, paths)
+
+	for _, c := range changes {
+		if c.Type == "insert" {
+			assert.Equal(t, map[string]interface{}{"containerPort": 443}, c.NewValue)
+		}
+	}
+}
+
+func TestDiffListWithoutMergeKeys(t *testing.T) {
+	oldObject := uo.FromStringMust(`
+spec:
+  args:
+  - a
+  - b
+`)
+	newObject := uo.FromStringMust(`
+spec:
+  args:
+  - a
+  - c
+`)
+
+	changes, err := Diff(oldObject, newObject)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "spec.args[1]", changes[0].JsonPath)
+}
@@ -98,6 +98,40 @@ func convertToKeyList(remote *uo.UnstructuredObject, path fieldpath.Path) (uo.Ke
 	return ret, true, nil
 }
 
+func isOverwriteAllowed(manager string) bool {
+	for _, oa := range overwriteAllowedManagers {
+		if oa.MatchString(manager) {
+			return true
+		}
+	}
+	return false
+}
+
+// getForceApplyFields returns whether the whole object should be force-applied and the JSON paths of all fields
+// matched by the kluctl.io/force-apply-field annotations
+func getForceApplyFields(local *uo.UnstructuredObject) (bool, map[string]bool, error) {
+	forceApplyAll := false
+	if x := local.GetK8sAnnotation("kluctl.io/force-apply"); x != nil {
+		forceApplyAll, _ = strconv.ParseBool(*x)
+	}
+
+	forceApplyFields := make(map[string]bool)
+	for _, v := range local.GetK8sAnnotationsWithRegex(forceApplyFieldAnnotationRegex) {
+		j, err := uo.NewMyJsonPath(v)
+		if err != nil {
+			return false, nil, err
+		}
+		fields, err := j.ListMatchingFields(local)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, f := range fields {
+			forceApplyFields[f.ToJsonPath()] = true
+		}
+	}
+	return forceApplyAll, forceApplyFields, nil
+}
+
 func ResolveFieldManagerConflicts(local *uo.UnstructuredObject, remote *uo.UnstructuredObject, conflictStatus metav1.Status) (*uo.UnstructuredObject, []LostOwnership, error) {
 	managedFields := remote.GetK8sManagedFields()
 
@@ -154,24 +188,9 @@ func ResolveFieldManagerConflicts(local *uo.UnstructuredObject, remote *uo.Unstr
 
 	ret := local.Clone()
 
-	forceApplyAll := false
-	if x := local.GetK8sAnnotation("kluctl.io/force-apply"); x != nil {
-		forceApplyAll, _ = strconv.ParseBool(*x)
-	}
-
-	forceApplyFields := make(map[string]bool)
-	for _, v := range local.GetK8sAnnotationsWithRegex(forceApplyFieldAnnotationRegex) {
-		j, err := uo.NewMyJsonPath(v)
-		if err != nil {
-			return nil, nil, err
-		}
-		fields, err := j.ListMatchingFields(ret)
-		if err != nil {
-			return nil, nil, err
-		}
-		for _, f := range fields {
-			forceApplyFields[f.ToJsonPath()] = true
-		}
+	forceApplyAll, forceApplyFields, err := getForceApplyFields(local)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var lostOwnership []LostOwnership
@@ -217,14 +236,7 @@ func ResolveFieldManagerConflicts(local *uo.UnstructuredObject, remote *uo.Unstr
 		overwrite := true
 		if !forceApplyAll {
 			for _, mfn := range mf.managers {
-				found := false
-				for _, oa := range overwriteAllowedManagers {
-					if oa.MatchString(mfn) {
-						found = true
-						break
-					}
-				}
-				if !found {
+				if !isOverwriteAllowed(mfn) {
 					overwrite = false
 					break
 				}
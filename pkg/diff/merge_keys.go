@@ -0,0 +1,152 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+	"strings"
+)
+
+// mergeKeyPrefix is used to mark map keys that were generated from list elements. The NUL character ensures that such
+// keys can never collide with real field names.
+const mergeKeyPrefix = "\x00mergeKey:"
+
+// listMergeKeys specifies the keys used to match list elements while diffing, similar to how strategic merge patches
+// match list elements. If multiple keys are specified, the first one that is present in all elements is used.
+var listMergeKeys = map[string][]string{
+	"containers":          {"name"},
+	"initContainers":      {"name"},
+	"ephemeralContainers": {"name"},
+	"env":                 {"name"},
+	"ports":               {"containerPort", "port"},
+	"volumes":             {"name"},
+	"volumeMounts":        {"mountPath"},
+	"volumeDevices":       {"devicePath"},
+	"imagePullSecrets":    {"name"},
+	"hostAliases":         {"ip"},
+}
+
+func findListMergeKey(l []interface{}, keys []string) (string, bool) {
+outer:
+	for _, k := range keys {
+		seen := map[string]bool{}
+		for _, e := range l {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			v, ok := m[k]
+			if !ok {
+				continue outer
+			}
+			s := fmt.Sprint(v)
+			if seen[s] {
+				// not unique, so we can't use it
+				continue outer
+			}
+			seen[s] = true
+		}
+		return k, true
+	}
+	return "", false
+}
+
+// keyListsByMergeKeys returns a copy of the given object with all lists that have known merge keys converted into
+// maps. This allows the differ to match list elements independent of their order.
+func keyListsByMergeKeys(o interface{}) interface{} {
+	return keyListsByMergeKeys2(o, "")
+}
+
+func keyListsByMergeKeys2(o interface{}, fieldName string) interface{} {
+	switch v := o.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			ret[k] = keyListsByMergeKeys2(x, k)
+		}
+		return ret
+	case []interface{}:
+		if keys, ok := listMergeKeys[fieldName]; ok && len(v) != 0 {
+			if k, ok := findListMergeKey(v, keys); ok {
+				ret := make(map[string]interface{}, len(v))
+				for _, e := range v {
+					mk := fmt.Sprintf("%s%s=%v", mergeKeyPrefix, k, e.(map[string]interface{})[k])
+					ret[mk] = keyListsByMergeKeys2(e, "")
+				}
+				return ret
+			}
+		}
+		ret := make([]interface{}, len(v))
+		for i, x := range v {
+			ret[i] = keyListsByMergeKeys2(x, "")
+		}
+		return ret
+	default:
+		return o
+	}
+}
+
+func isMergeKeyMap(m map[string]interface{}) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for k := range m {
+		if !strings.HasPrefix(k, mergeKeyPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreMergeKeyLists reverts the conversion done by keyListsByMergeKeys. The original order of the list elements
+// is not preserved.
+func restoreMergeKeyLists(o interface{}) interface{} {
+	switch v := o.(type) {
+	case map[string]interface{}:
+		if isMergeKeyMap(v) {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			ret := make([]interface{}, 0, len(v))
+			for _, k := range keys {
+				ret = append(ret, restoreMergeKeyLists(v[k]))
+			}
+			return ret
+		}
+		ret := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			ret[k] = restoreMergeKeyLists(x)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, x := range v {
+			ret[i] = restoreMergeKeyLists(x)
+		}
+		return ret
+	default:
+		return o
+	}
+}
+
+// formatChangePath converts the key path into a JSON path, rendering merge keys as "[key=value]"
+func formatChangePath(kp uo.KeyPath) string {
+	p := ""
+	for _, k := range kp {
+		if mk, ok := k.(string); ok && strings.HasPrefix(mk, mergeKeyPrefix) {
+			p += "[" + strings.TrimPrefix(mk, mergeKeyPrefix) + "]"
+			continue
+		}
+		s := uo.KeyPath{k}.ToJsonPath()
+		if p != "" {
+			s = strings.TrimPrefix(s, "$")
+			if !strings.HasPrefix(s, "[") {
+				s = "." + s
+			}
+		}
+		p += s
+	}
+	return p
+}
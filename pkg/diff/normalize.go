@@ -9,28 +9,10 @@ import (
 	"strings"
 )
 
-func listToMap(l []*uo.UnstructuredObject, key string) (map[string]interface{}, error) {
-	m := make(map[string]interface{})
-	for _, e := range l {
-		kv, found, _ := e.GetNestedString(key)
-		if !found {
-			return nil, fmt.Errorf("%s not found in list element", key)
-		}
-		m[kv] = e.Object
-	}
-	return m, nil
-}
-
 func normalizeEnv(container *uo.UnstructuredObject) {
-	env := container.GetNestedObjectListNoErr("env")
+	// env is matched by name while diffing, so only envFrom needs to be normalized here
 	envFrom := container.GetNestedObjectListNoErr("envFrom")
 
-	if len(env) != 0 {
-		newEnv, err := listToMap(env, "name")
-		if err == nil {
-			_ = container.SetNestedField(newEnv, "env")
-		}
-	}
 	if len(envFrom) != 0 {
 		envTypes := []string{"configMapRef", "secretRef"}
 		m := make(map[string]interface{})
@@ -62,6 +44,15 @@ func normalizeContainers(containers []*uo.UnstructuredObject) {
 	}
 }
 
+func normalizePodTemplate(o *uo.UnstructuredObject, keys ...interface{}) {
+	template, found, _ := o.GetNestedObject(keys...)
+	if !found {
+		return
+	}
+	normalizeContainers(template.GetNestedObjectListNoErr("spec", "initContainers"))
+	normalizeContainers(template.GetNestedObjectListNoErr("spec", "containers"))
+}
+
 func normalizeSecretAndConfigMaps(o *uo.UnstructuredObject) {
 	data, found, _ := o.GetNestedObject("data")
 	if found && len(data.Object) == 0 {
@@ -120,7 +111,7 @@ func normalizeMisc(o *uo.UnstructuredObject) {
 var ignoreDiffFieldAnnotationRegex = regexp.MustCompile(`^kluctl.io/ignore-diff-field(-\d*)?$`)
 
 // NormalizeObject Performs some deterministic sorting and other normalizations to avoid ugly diffs due to order changes
-func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig, localObject *uo.UnstructuredObject) *uo.UnstructuredObject {
+func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreForDiffItemConfig, normalizeForDiffs []*types.NormalizeForDiffItemConfig, localObject *uo.UnstructuredObject) *uo.UnstructuredObject {
 	gvk := o_.GetK8sGVK()
 	name := o_.GetK8sName()
 	ns := o_.GetK8sNamespace()
@@ -129,33 +120,33 @@ func NormalizeObject(o_ *uo.UnstructuredObject, ignoreForDiffs []*types.IgnoreFo
 	normalizeMetadata(o)
 	normalizeMisc(o)
 
+	var podTemplatePaths []string
+	for _, nfd := range normalizeForDiffs {
+		if !matchesObject(gvk, ns, name, nfd.Group, nfd.Kind, nfd.Namespace, nfd.Name) {
+			continue
+		}
+		applyNormalizeRule(o, nfd)
+		podTemplatePaths = append(podTemplatePaths, nfd.PodTemplatePath...)
+	}
+
 	switch gvk.Kind {
-	case "Deployment", "StatefulSet", "DaemonSet", "job":
-		normalizeContainers(o.GetNestedObjectListNoErr("spec", "template", "spec", "containers"))
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		normalizePodTemplate(o, "spec", "template")
+	case "CronJob":
+		normalizePodTemplate(o, "spec", "jobTemplate", "spec", "template")
 	case "Secret", "ConfigMap":
 		normalizeSecretAndConfigMaps(o)
 	case "ServiceAccount":
 		normalizeServiceAccount(o)
 	}
-
-	checkMatch := func(v string, m *string) bool {
-		if v == "" || m == nil {
-			return true
+	for _, p := range podTemplatePaths {
+		for _, kp := range listMatchingFields(o, p) {
+			normalizePodTemplate(o, kp...)
 		}
-		return v == *m
 	}
 
 	for _, ifd := range ignoreForDiffs {
-		if !checkMatch(gvk.Group, ifd.Group) {
-			continue
-		}
-		if !checkMatch(gvk.Kind, ifd.Kind) {
-			continue
-		}
-		if !checkMatch(ns, ifd.Namespace) {
-			continue
-		}
-		if !checkMatch(name, ifd.Name) {
+		if !matchesObject(gvk, ns, name, ifd.Group, ifd.Kind, ifd.Namespace, ifd.Name) {
 			continue
 		}
 
@@ -0,0 +1,185 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"time"
+)
+
+func matchesObject(gvk schema.GroupVersionKind, ns string, name string, group *string, kind *string, matchNs *string, matchName *string) bool {
+	checkMatch := func(v string, m *string) bool {
+		if v == "" || m == nil {
+			return true
+		}
+		return v == *m
+	}
+	return checkMatch(gvk.Group, group) && checkMatch(gvk.Kind, kind) && checkMatch(ns, matchNs) && checkMatch(name, matchName)
+}
+
+func listMatchingFields(o *uo.UnstructuredObject, p string) []uo.KeyPath {
+	j, err := uo.NewMyJsonPath(p)
+	if err != nil {
+		return nil
+	}
+	fields, err := j.ListMatchingFields(o)
+	if err != nil {
+		return nil
+	}
+	return fields
+}
+
+// applyNormalizeRule applies a single normalizeForDiff rule to the object. The object is modified in-place.
+func applyNormalizeRule(o *uo.UnstructuredObject, rule *types.NormalizeForDiffItemConfig) {
+	for _, r := range rule.Renames {
+		for _, p := range r.FieldPath {
+			for _, kp := range listMatchingFields(o, p) {
+				renameField(o, kp, r.NewName)
+			}
+		}
+	}
+	for _, p := range rule.Quantities {
+		for _, kp := range listMatchingFields(o, p) {
+			normalizeValue(o, kp, normalizeQuantity)
+		}
+	}
+	for _, p := range rule.Durations {
+		for _, kp := range listMatchingFields(o, p) {
+			normalizeValue(o, kp, normalizeDuration)
+		}
+	}
+	for _, d := range rule.Defaults {
+		for _, p := range d.FieldPath {
+			for _, kp := range listMatchingFields(o, p) {
+				v, found, _ := o.GetNestedField(kp...)
+				if found && valuesEqual(v, d.Value) {
+					_ = o.RemoveNestedField(kp...)
+				}
+			}
+		}
+	}
+	for _, s := range rule.SortListBy {
+		for _, p := range s.FieldPath {
+			for _, kp := range listMatchingFields(o, p) {
+				sortListBy(o, kp, s.Key)
+			}
+		}
+	}
+}
+
+// renameField moves the value found at kp to the sibling field newName. If newName is already set, the old field is
+// simply removed, as the API server usually mirrors deprecated fields into their replacements.
+func renameField(o *uo.UnstructuredObject, kp uo.KeyPath, newName string) {
+	if len(kp) == 0 {
+		return
+	}
+	v, found, _ := o.GetNestedField(kp...)
+	if !found {
+		return
+	}
+	parentKp := kp[:len(kp)-1]
+	parent, found, _ := o.GetNestedField(parentKp...)
+	if !found {
+		return
+	}
+	m, ok := parent.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := m[newName]; !ok {
+		m[newName] = v
+	}
+	_ = o.RemoveNestedField(kp...)
+}
+
+func normalizeValue(o *uo.UnstructuredObject, kp uo.KeyPath, f func(v interface{}) (interface{}, bool)) {
+	v, found, _ := o.GetNestedField(kp...)
+	if !found {
+		return
+	}
+	nv, ok := f(v)
+	if !ok {
+		return
+	}
+	_ = o.SetNestedField(nv, kp...)
+}
+
+// normalizeQuantity converts quantities into their canonical form, e.g. "1000m" becomes "1" and 2 becomes "2"
+func normalizeQuantity(v interface{}) (interface{}, bool) {
+	switch v.(type) {
+	case string, int, int32, int64, float64:
+	default:
+		return nil, false
+	}
+	q, err := resource.ParseQuantity(fmt.Sprint(v))
+	if err != nil {
+		return nil, false
+	}
+	return q.String(), true
+}
+
+// normalizeDuration converts durations into their canonical form, e.g. "60s" becomes "1m0s"
+func normalizeDuration(v interface{}) (interface{}, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, false
+	}
+	return d.String(), true
+}
+
+func sortListBy(o *uo.UnstructuredObject, kp uo.KeyPath, key string) {
+	v, found, _ := o.GetNestedField(kp...)
+	if !found {
+		return
+	}
+	l, ok := v.([]interface{})
+	if !ok {
+		return
+	}
+	j, err := uo.NewMyJsonPath(key)
+	if err != nil {
+		return
+	}
+
+	getKey := func(e interface{}) (string, bool) {
+		k, found := j.GetFirstFromAny(e)
+		if !found {
+			return "", false
+		}
+		return fmt.Sprint(k), true
+	}
+
+	l2 := make([]interface{}, len(l))
+	copy(l2, l)
+	sort.SliceStable(l2, func(i, j int) bool {
+		ki, oki := getKey(l2[i])
+		kj, okj := getKey(l2[j])
+		if oki != okj {
+			// elements without a key go to the end
+			return oki
+		}
+		return ki < kj
+	})
+	_ = o.SetNestedField(l2, kp...)
+}
+
+// valuesEqual compares values independent of their concrete go types, e.g. int vs int64
+func valuesEqual(a interface{}, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
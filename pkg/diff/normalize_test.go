@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeJobAndCronJobEnv(t *testing.T) {
+	for _, s := range []string{`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: j1
+spec:
+  template:
+    spec:
+      containers:
+      - name: c1
+        envFrom:
+        - configMapRef:
+            name: b
+        - secretRef:
+            name: a
+`, `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: j1
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: c1
+            envFrom:
+            - configMapRef:
+                name: b
+            - secretRef:
+                name: a
+`} {
+		o := uo.FromStringMust(s)
+		n := NormalizeObject(o, nil, nil, o)
+		c, _, _ := uo.NewMyJsonPathMust("$..containers[0]").GetFirstObject(n)
+		env, _, _ := c.GetNestedField("envFrom")
+		assert.IsType(t, map[string]interface{}{}, env)
+	}
+}
+
+func TestNormalizeRules(t *testing.T) {
+	ro := uo.FromStringMust(`
+apiVersion: example.com/v1
+kind: MyWorkload
+metadata:
+  name: w1
+spec:
+  cpu: 1000m
+  interval: 60s
+  oldField: x
+  replicas: 1
+  items:
+  - name: b
+  - name: a
+  template:
+    spec:
+      containers:
+      - name: c1
+        envFrom:
+        - configMapRef:
+            name: a
+`)
+	lo := uo.FromStringMust(`
+apiVersion: example.com/v1
+kind: MyWorkload
+metadata:
+  name: w1
+spec:
+  cpu: 1
+  interval: 1m
+  newField: x
+  items:
+  - name: a
+  - name: b
+  template:
+    spec:
+      containers:
+      - name: c1
+        envFrom:
+        - configMapRef:
+            name: a
+`)
+
+	rules := []*types.NormalizeForDiffItemConfig{
+		{
+			Kind:            &[]string{"MyWorkload"}[0],
+			SortListBy:      []*types.SortListByConfig{{FieldPath: []string{"spec.items"}, Key: "name"}},
+			Quantities:      []string{"spec.cpu"},
+			Durations:       []string{"spec.interval"},
+			Defaults:        []*types.DefaultValueConfig{{FieldPath: []string{"spec.replicas"}, Value: 1}},
+			Renames:         []*types.RenameFieldConfig{{FieldPath: []string{"spec.oldField"}, NewName: "newField"}},
+			PodTemplatePath: []string{"spec.template"},
+		},
+	}
+
+	nro := NormalizeObject(ro, nil, rules, lo)
+	nlo := NormalizeObject(lo, nil, rules, lo)
+	assert.Equal(t, nlo.Object, nro.Object)
+
+	env, _, _ := nlo.GetNestedField("spec", "template", "spec", "containers", 0, "envFrom")
+	assert.IsType(t, map[string]interface{}{}, env)
+
+	other := ro.Clone()
+	other.SetK8sGVKs("example.com", "v1", "Other")
+	nOther := NormalizeObject(other, nil, rules, other)
+	_, found, _ := nOther.GetNestedField("spec", "oldField")
+	assert.True(t, found)
+}
+
+func TestValidateNormalizeForDiffPaths(t *testing.T) {
+	var c types.DeploymentProjectConfig
+	err := yaml.ReadYamlString(`
+normalizeForDiff:
+- kind: Deployment
+  quantities:
+  - spec.template.spec.containers[*].resources
+  durations: spec.progressDeadline
+`, &c)
+	assert.NoError(t, err)
+
+	for _, s := range []string{
+		"normalizeForDiff:\n- quantities: spec.containers[*.resources\n",
+		"normalizeForDiff:\n- renames:\n  - fieldPath: spec.[\n    newName: x\n",
+		"normalizeForDiff:\n- sortListBy:\n  - fieldPath: spec..[\n    key: name\n",
+	} {
+		err = yaml.ReadYamlString(s, &c)
+		assert.ErrorContains(t, err, "invalid JSONPath", s)
+	}
+}
@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sort"
+)
+
+// GetFieldOwnership reads the managedFields of the remote object and returns the field managers for all fields that
+// are set in the local object. Fields that are not owned by any manager are omitted.
+func GetFieldOwnership(local *uo.UnstructuredObject, remote *uo.UnstructuredObject) ([]types.FieldOwnership, error) {
+	forceApplyAll, forceApplyFields, err := getForceApplyFields(local)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := map[string]*types.FieldOwnership{}
+
+	for _, mf := range remote.GetK8sManagedFields() {
+		fields, ok, err := mf.GetNestedObject("fieldsV1")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		fieldSet, _, err := convertManagedFields(fields.Object)
+		if err != nil {
+			return nil, err
+		}
+		if fieldSet == nil {
+			continue
+		}
+
+		mgr, ok, err := mf.GetNestedString("manager")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("manager field is missing")
+		}
+		operation, _, _ := mf.GetNestedString("operation")
+
+		fieldSet.Leaves().Iterate(func(path fieldpath.Path) {
+			localKeyPath, found, err := convertToKeyList(local, path)
+			if err != nil || !found {
+				// the local object does not set this field or has an incompatible structure
+				return
+			}
+			jp := localKeyPath.ToJsonPath()
+			fo, ok := byPath[jp]
+			if !ok {
+				fo = &types.FieldOwnership{JsonPath: jp}
+				byPath[jp] = fo
+			}
+			fo.Owners = append(fo.Owners, types.FieldOwner{
+				Manager:   mgr,
+				Operation: operation,
+			})
+			if !isOverwriteAllowed(mgr) && !forceApplyAll && !forceApplyFields[jp] {
+				fo.Conflict = true
+			}
+		})
+	}
+
+	ret := make([]types.FieldOwnership, 0, len(byPath))
+	for _, fo := range byPath {
+		ret = append(ret, *fo)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].JsonPath < ret[j].JsonPath
+	})
+	return ret, nil
+}
+
+// FilterForeignFieldOwnership returns only the fields that are owned by at least one field manager that is not kluctl
+func FilterForeignFieldOwnership(l []types.FieldOwnership) []types.FieldOwnership {
+	var ret []types.FieldOwnership
+	for _, fo := range l {
+		for _, o := range fo.Owners {
+			if o.Manager != "kluctl" {
+				ret = append(ret, fo)
+				break
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetFieldOwnership(t *testing.T) {
+	local := uo.FromStringMust(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d1
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+`)
+	remote := uo.FromStringMust(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d1
+  managedFields:
+  - manager: kluctl
+    operation: Apply
+    fieldsV1:
+      f:spec:
+        f:template:
+          f:spec:
+            f:containers:
+              k:{"name":"app"}:
+                .: {}
+                f:image: {}
+                f:name: {}
+  - manager: kube-controller-manager
+    operation: Update
+    fieldsV1:
+      f:spec:
+        f:replicas: {}
+        f:paused: {}
+spec:
+  replicas: 5
+  paused: false
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+`)
+
+	fields, err := GetFieldOwnership(local, remote)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.FieldOwnership{
+		{JsonPath: "spec.replicas", Owners: []types.FieldOwner{{Manager: "kube-controller-manager", Operation: "Update"}}, Conflict: true},
+		{JsonPath: "spec.template.spec.containers[0].image", Owners: []types.FieldOwner{{Manager: "kluctl", Operation: "Apply"}}},
+		{JsonPath: "spec.template.spec.containers[0].name", Owners: []types.FieldOwner{{Manager: "kluctl", Operation: "Apply"}}},
+	}, fields)
+
+	foreign := FilterForeignFieldOwnership(fields)
+	assert.Len(t, foreign, 1)
+	assert.Equal(t, "spec.replicas", foreign[0].JsonPath)
+
+	local.SetK8sAnnotation("kluctl.io/force-apply-field", "spec.replicas")
+	fields, err = GetFieldOwnership(local, remote)
+	assert.NoError(t, err)
+	assert.False(t, fields[0].Conflict)
+}
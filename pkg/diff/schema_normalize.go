@@ -0,0 +1,305 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"reflect"
+)
+
+// NormalizeWithSchema uses the OpenAPI schema of the object to avoid noise in diffs. It removes fields from the applied
+// and the remote object that are omitted in the local object and equal their schema defaults on both sides. Afterwards, leaf values
+// of the remote object that are equal to the applied object in a type-aware manner (e.g. 1 vs "1" for int-or-string
+// fields or "1000m" vs 1 for quantities) are replaced with the applied version, so that they don't show up in diffs.
+// The applied and remote objects are modified in-place.
+func NormalizeWithSchema(s *uo.UnstructuredObject, localObject *uo.UnstructuredObject, appliedObject *uo.UnstructuredObject, remoteObject *uo.UnstructuredObject) {
+	var sm map[string]interface{}
+	if s != nil {
+		sm = s.Object
+	}
+	removeSchemaDefaults(sm, localObject.Object, appliedObject.Object, remoteObject.Object)
+	unifyValues(sm, appliedObject.Object, remoteObject.Object)
+}
+
+func schemaProperty(s map[string]interface{}, key string) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		if p, ok := props[key].(map[string]interface{}); ok {
+			return p
+		}
+	}
+	if ap, ok := s["additionalProperties"].(map[string]interface{}); ok {
+		return ap
+	}
+	return nil
+}
+
+func schemaItems(s map[string]interface{}) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	items, _ := s["items"].(map[string]interface{})
+	return items
+}
+
+// schemaMergeKeys returns the keys used to identify list elements, either from x-kubernetes-list-map-keys (CRDs and
+// built-in types) or from x-kubernetes-patch-merge-key (built-in types)
+func schemaMergeKeys(s map[string]interface{}) []string {
+	if s == nil {
+		return nil
+	}
+	if l, ok := s["x-kubernetes-list-map-keys"].([]interface{}); ok && len(l) != 0 {
+		var ret []string
+		for _, x := range l {
+			if k, ok := x.(string); ok {
+				ret = append(ret, k)
+			}
+		}
+		return ret
+	}
+	if k, ok := s["x-kubernetes-patch-merge-key"].(string); ok {
+		return []string{k}
+	}
+	return nil
+}
+
+func listElementKey(e interface{}, keys []string) (string, bool) {
+	m, ok := e.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	ret := ""
+	found := false
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			ret += fmt.Sprintf("%s=%v;", k, v)
+			found = true
+		}
+	}
+	return ret, found
+}
+
+// matchListElements returns pairs of indexes of matching elements in a and b. Elements are matched by the merge keys
+// from the schema if available, or by index otherwise.
+func matchListElements(s map[string]interface{}, a []interface{}, b []interface{}) [][2]int {
+	var ret [][2]int
+	keys := schemaMergeKeys(s)
+	if len(keys) != 0 {
+		bm := map[string]int{}
+		for i, e := range b {
+			if k, ok := listElementKey(e, keys); ok {
+				bm[k] = i
+			}
+		}
+		for i, e := range a {
+			k, ok := listElementKey(e, keys)
+			if !ok {
+				continue
+			}
+			if j, ok := bm[k]; ok {
+				ret = append(ret, [2]int{i, j})
+			}
+		}
+		return ret
+	}
+	if len(a) != len(b) {
+		return nil
+	}
+	for i := range a {
+		ret = append(ret, [2]int{i, i})
+	}
+	return ret
+}
+
+// removeSchemaDefaults removes fields from the applied and remote objects that are omitted in the local object and
+// equal their schema defaults. A field is only removed if it equals the default on both sides, with missing fields
+// being considered as defaulted. Otherwise, real changes (e.g. a remote value of 5 vs. an applied default of 10)
+// would be hidden or shown as removals.
+func removeSchemaDefaults(s map[string]interface{}, local interface{}, applied interface{}, remote interface{}) {
+	if s == nil {
+		return
+	}
+
+	am, aIsMap := applied.(map[string]interface{})
+	rm, rIsMap := remote.(map[string]interface{})
+	if aIsMap || rIsMap {
+		lm, _ := local.(map[string]interface{})
+		keys := map[string]bool{}
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range rm {
+			keys[k] = true
+		}
+		for k := range keys {
+			ps := schemaProperty(s, k)
+			if ps == nil {
+				continue
+			}
+			av, aFound := am[k]
+			rv, rFound := rm[k]
+			if lv, found := lm[k]; found {
+				removeSchemaDefaults(ps, lv, av, rv)
+				continue
+			}
+			if def, ok := ps["default"]; ok {
+				aDefault := !aFound || valuesEqualWithSchema(ps, av, def)
+				rDefault := !rFound || valuesEqualWithSchema(ps, rv, def)
+				if aDefault && rDefault {
+					delete(am, k)
+					delete(rm, k)
+					continue
+				}
+			}
+
+			// the whole sub-tree is omitted in the local object, so nested defaults can be removed as well
+			avm, aIsMap := av.(map[string]interface{})
+			rvm, rIsMap := rv.(map[string]interface{})
+			if aIsMap || rIsMap {
+				removeSchemaDefaults(ps, nil, av, rv)
+				if aIsMap && len(avm) == 0 {
+					delete(am, k)
+				}
+				if rIsMap && len(rvm) == 0 {
+					delete(rm, k)
+				}
+			}
+		}
+		return
+	}
+
+	al, aIsList := applied.([]interface{})
+	rl, rIsList := remote.([]interface{})
+	if !aIsList && !rIsList {
+		return
+	}
+	ll, _ := local.([]interface{})
+	is := schemaItems(s)
+	appliedMatches := map[int]int{}
+	for _, p := range matchListElements(is, ll, al) {
+		appliedMatches[p[0]] = p[1]
+	}
+	remoteMatches := map[int]int{}
+	for _, p := range matchListElements(is, ll, rl) {
+		remoteMatches[p[0]] = p[1]
+	}
+	for i := range ll {
+		var av, rv interface{}
+		if j, ok := appliedMatches[i]; ok {
+			av = al[j]
+		}
+		if j, ok := remoteMatches[i]; ok {
+			rv = rl[j]
+		}
+		if av == nil && rv == nil {
+			continue
+		}
+		removeSchemaDefaults(is, ll[i], av, rv)
+	}
+}
+
+// unifyValues replaces all leaf values in b with the values from a if they are equal in a type-aware manner
+func unifyValues(s map[string]interface{}, a interface{}, b interface{}) {
+	switch bv := b.(type) {
+	case map[string]interface{}:
+		am, ok := a.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for k, v := range bv {
+			av, ok := am[k]
+			if !ok {
+				continue
+			}
+			ps := schemaProperty(s, k)
+			if isScalar(av) && isScalar(v) {
+				if !reflect.DeepEqual(av, v) && valuesEqualWithSchema(ps, av, v) {
+					bv[k] = av
+				}
+				continue
+			}
+			unifyValues(ps, av, v)
+		}
+	case []interface{}:
+		al, ok := a.([]interface{})
+		if !ok {
+			return
+		}
+		is := schemaItems(s)
+		for _, p := range matchListElements(is, al, bv) {
+			av, v := al[p[0]], bv[p[1]]
+			if isScalar(av) && isScalar(v) {
+				if !reflect.DeepEqual(av, v) && valuesEqualWithSchema(is, av, v) {
+					bv[p[1]] = av
+				}
+				continue
+			}
+			unifyValues(is, av, v)
+		}
+	}
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return true
+}
+
+func isIntOrString(s map[string]interface{}) bool {
+	if s == nil {
+		return false
+	}
+	if b, ok := s["x-kubernetes-int-or-string"].(bool); ok && b {
+		return true
+	}
+	f, _ := s["format"].(string)
+	return f == "int-or-string" || f == "quantity"
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+// valuesEqualWithSchema compares values in a type-aware manner. Numbers are compared independent of their concrete
+// go types. For int-or-string fields, numbers and strings are considered equal if they represent the same quantity.
+func valuesEqualWithSchema(s map[string]interface{}, a interface{}, b interface{}) bool {
+	if valuesEqual(a, b) {
+		return true
+	}
+	if !isScalar(a) || !isScalar(b) {
+		return false
+	}
+	fa, oka := toFloat(a)
+	fb, okb := toFloat(b)
+	if oka && okb {
+		return fa == fb
+	}
+	if !isIntOrString(s) {
+		return false
+	}
+	qa, err := resource.ParseQuantity(fmt.Sprint(a))
+	if err != nil {
+		return false
+	}
+	qb, err := resource.ParseQuantity(fmt.Sprint(b))
+	if err != nil {
+		return false
+	}
+	return qa.Cmp(qb) == 0
+}
@@ -0,0 +1,135 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testSchema = uo.FromStringMust(`
+type: object
+properties:
+  spec:
+    type: object
+    properties:
+      revisionHistoryLimit:
+        type: integer
+        default: 10
+      strategy:
+        type: object
+        properties:
+          type:
+            type: string
+            default: RollingUpdate
+      containers:
+        type: array
+        x-kubernetes-patch-merge-key: name
+        items:
+          type: object
+          properties:
+            name:
+              type: string
+            imagePullPolicy:
+              type: string
+              default: IfNotPresent
+            resources:
+              type: object
+              properties:
+                limits:
+                  type: object
+                  additionalProperties:
+                    x-kubernetes-int-or-string: true
+`)
+
+func TestNormalizeWithSchema(t *testing.T) {
+	lo := uo.FromStringMust(`
+spec:
+  containers:
+  - name: c1
+    resources:
+      limits:
+        cpu: 1
+`)
+	ao := uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 10
+  strategy:
+    type: RollingUpdate
+  containers:
+  - name: c1
+    imagePullPolicy: IfNotPresent
+    resources:
+      limits:
+        cpu: 1
+`)
+	ro := uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 5
+  containers:
+  - name: c1
+    imagePullPolicy: Always
+    resources:
+      limits:
+        cpu: 1000m
+`)
+
+	NormalizeWithSchema(testSchema, lo, ao, ro)
+
+	// revisionHistoryLimit and imagePullPolicy differ from the remote values, so these are real changes
+	assert.Equal(t, uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 10
+  containers:
+  - name: c1
+    imagePullPolicy: IfNotPresent
+    resources:
+      limits:
+        cpu: 1
+`).Object, ao.Object)
+	assert.Equal(t, uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 5
+  containers:
+  - name: c1
+    imagePullPolicy: Always
+    resources:
+      limits:
+        cpu: 1
+`).Object, ro.Object)
+}
+
+func TestNormalizeWithSchemaBothDefaulted(t *testing.T) {
+	lo := uo.FromStringMust(`
+spec:
+  containers:
+  - name: c1
+`)
+	ao := uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 10
+  strategy:
+    type: RollingUpdate
+  containers:
+  - name: c1
+    imagePullPolicy: IfNotPresent
+`)
+	ro := uo.FromStringMust(`
+spec:
+  revisionHistoryLimit: 10
+  strategy:
+    type: RollingUpdate
+  containers:
+  - name: c1
+    imagePullPolicy: IfNotPresent
+`)
+
+	NormalizeWithSchema(testSchema, lo, ao, ro)
+
+	expected := uo.FromStringMust(`
+spec:
+  containers:
+  - name: c1
+`)
+	assert.Equal(t, expected.Object, ao.Object)
+	assert.Equal(t, expected.Object, ro.Object)
+}
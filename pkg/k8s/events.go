@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sort"
+	"time"
+)
+
+// ListEvents returns all events that refer to the given object, sorted by the time they were last seen
+func (k *K8sCluster) ListEvents(ref k8s.ObjectRef) ([]corev1.Event, []ApiWarning, error) {
+	var ret []corev1.Event
+	apiWarnings, err := k.clients.withClientFromPool(func(p *parallelClientEntry) error {
+		sel := fields.Set{
+			"involvedObject.kind": ref.GVK.Kind,
+			"involvedObject.name": ref.Name,
+		}
+		if ref.Namespace != "" {
+			sel["involvedObject.namespace"] = ref.Namespace
+		}
+		l, err := p.corev1.Events(ref.Namespace).List(k.ctx, v1.ListOptions{
+			FieldSelector: sel.AsSelector().String(),
+		})
+		if err != nil {
+			return err
+		}
+		ret = l.Items
+		return nil
+	})
+	if err != nil {
+		return nil, apiWarnings, err
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return GetEventTime(ret[i]).Before(GetEventTime(ret[j]))
+	})
+	return ret, apiWarnings, nil
+}
+
+// GetEventTime returns the time an event was last seen, falling back to the older fields for events that do not set it
+func GetEventTime(e corev1.Event) time.Time {
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
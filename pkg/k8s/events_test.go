@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+	"time"
+)
+
+func TestListEvents(t *testing.T) {
+	now := time.Now()
+	buildEvent := func(name string, reason string, lastTimestamp time.Time) runtime.Object {
+		return &corev1.Event{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Deployment",
+				Name:      "d1",
+				Namespace: "default",
+			},
+			Reason:        reason,
+			Type:          corev1.EventTypeWarning,
+			LastTimestamp: v1.NewTime(lastTimestamp),
+		}
+	}
+
+	k, err := NewK8sCluster(context.TODO(), NewFakeClientFactory(
+		buildEvent("e1", "Second", now.Add(-time.Minute)),
+		buildEvent("e2", "First", now.Add(-time.Hour)),
+		buildEvent("e3", "Third", now),
+	), false)
+	assert.NoError(t, err)
+
+	events, _, err := k.ListEvents(k8s.ObjectRef{
+		GVK:       schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Name:      "d1",
+		Namespace: "default",
+	})
+	assert.NoError(t, err)
+
+	var reasons []string
+	for _, e := range events {
+		reasons = append(reasons, e.Reason)
+	}
+	assert.Equal(t, []string{"First", "Second", "Third"}, reasons)
+}
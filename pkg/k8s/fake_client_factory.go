@@ -18,9 +18,8 @@ import (
 )
 
 type fakeClientFactory struct {
-	clientSet *fake.Clientset
-	objects   []runtime.Object
-	scheme    *runtime.Scheme
+	clientSet     *fake.Clientset
+	dynamicClient *fake_dynamic.FakeDynamicClient
 }
 
 func (f *fakeClientFactory) RESTConfig() *rest.Config {
@@ -43,7 +42,8 @@ func (f *fakeClientFactory) CoreV1Client(wh rest.WarningHandler) (corev1.CoreV1I
 }
 
 func (f *fakeClientFactory) DynamicClient(wh rest.WarningHandler) (dynamic.Interface, error) {
-	return fake_dynamic.NewSimpleDynamicClient(f.scheme, f.objects...), nil
+	// all clients share the same fake dynamic client, so that modifications are visible to all of them
+	return f.dynamicClient, nil
 }
 
 func NewFakeClientFactory(objects ...runtime.Object) ClientFactory {
@@ -55,9 +55,8 @@ func NewFakeClientFactory(objects ...runtime.Object) ClientFactory {
 	clientSet.Fake.Resources = ConvertSchemeToAPIResources(scheme)
 
 	return &fakeClientFactory{
-		clientSet: clientSet,
-		objects:   objects,
-		scheme:    scheme,
+		clientSet:     clientSet,
+		dynamicClient: fake_dynamic.NewSimpleDynamicClient(scheme, objects...),
 	}
 }
 
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StuckDeletionError is returned when an object is marked for deletion but does not vanish due to finalizers that are
+// not being removed.
+type StuckDeletionError struct {
+	Ref        k8s.ObjectRef
+	Duration   time.Duration
+	Finalizers []string
+	// Managers maps finalizers to the field managers which added them. This usually identifies the controller that is
+	// responsible for removing the finalizer.
+	Managers map[string][]string
+}
+
+func (e *StuckDeletionError) Error() string {
+	var l []string
+	for _, f := range e.Finalizers {
+		if m, ok := e.Managers[f]; ok {
+			l = append(l, fmt.Sprintf("%s (added by %s)", f, strings.Join(m, ", ")))
+		} else {
+			l = append(l, f)
+		}
+	}
+	return fmt.Sprintf("%s is stuck in deletion for %s, blocked by finalizers: %s", e.Ref.String(), e.Duration.Round(time.Second).String(), strings.Join(l, ", "))
+}
+
+// getFinalizerManagers uses the managedFields of the object to determine which field managers have added the
+// finalizers of the object.
+func getFinalizerManagers(o *uo.UnstructuredObject) map[string][]string {
+	ret := make(map[string][]string)
+	for _, mf := range o.GetK8sManagedFields() {
+		mgr, _, _ := mf.GetNestedString("manager")
+		if mgr == "" {
+			continue
+		}
+		fields, ok, _ := mf.GetNestedObject("fieldsV1", "f:metadata", "f:finalizers")
+		if !ok {
+			continue
+		}
+		for k := range fields.Object {
+			if !strings.HasPrefix(k, "v:") {
+				continue
+			}
+			f, err := strconv.Unquote(k[2:])
+			if err != nil {
+				continue
+			}
+			ret[f] = append(ret[f], mgr)
+		}
+	}
+	for _, l := range ret {
+		sort.Strings(l)
+	}
+	return ret
+}
+
+// removeFinalizers removes all finalizers of the given object. A merge patch is used instead of a JSON patch, as the
+// latter would fail if the finalizers got removed in the meantime.
+func (k *K8sCluster) removeFinalizers(ref k8s.ObjectRef) ([]ApiWarning, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": nil,
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	_, apiWarnings, err := k.doPatch(ref, data, types.MergePatchType, PatchOptions{})
+	return apiWarnings, err
+}
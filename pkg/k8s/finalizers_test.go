@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestStuckDeletionError(t *testing.T) {
+	o := uo.FromStringMust(`
+apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: r1
+  namespace: ns
+  deletionTimestamp: "2022-01-01T00:00:00Z"
+  finalizers:
+  - example.com/cleanup
+  - other/finalizer
+  managedFields:
+  - manager: my-operator
+    operation: Update
+    fieldsV1:
+      f:metadata:
+        f:finalizers:
+          .: {}
+          v:"example.com/cleanup": {}
+`)
+
+	assert.Equal(t, []string{"example.com/cleanup", "other/finalizer"}, o.GetK8sFinalizers())
+	assert.False(t, o.GetK8sDeletionTime().IsZero())
+
+	managers := getFinalizerManagers(o)
+	assert.Equal(t, map[string][]string{
+		"example.com/cleanup": {"my-operator"},
+	}, managers)
+
+	err := &StuckDeletionError{
+		Ref:        k8s.NewObjectRef("example.com", "v1", "MyResource", "r1", "ns"),
+		Duration:   90 * time.Second,
+		Finalizers: o.GetK8sFinalizers(),
+		Managers:   managers,
+	}
+	assert.Contains(t, err.Error(), "stuck in deletion for 1m30s")
+	assert.Contains(t, err.Error(), "example.com/cleanup (added by my-operator), other/finalizer")
+}
+
+func TestRemoveFinalizers(t *testing.T) {
+	k, err := NewK8sCluster(context.TODO(), NewFakeClientFactory(
+		&corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "cm1", Namespace: "default", Finalizers: []string{"example.com/cleanup"}}},
+		&corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "cm2", Namespace: "default"}},
+	), false)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"cm1", "cm2"} {
+		ref := k8s.NewObjectRef("", "v1", "ConfigMap", name, "default")
+		_, err = k.removeFinalizers(ref)
+		assert.NoError(t, err)
+
+		// removing the finalizers again must not fail, as they might have been removed in the meantime
+		_, err = k.removeFinalizers(ref)
+		assert.NoError(t, err)
+
+		o, _, err := k.GetSingleObject(ref)
+		assert.NoError(t, err)
+		assert.Empty(t, o.GetK8sFinalizers())
+	}
+}
@@ -17,12 +17,14 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -134,6 +136,25 @@ func (k *K8sCluster) ListObjects(gvk schema.GroupVersionKind, namespace string,
 	return result, apiWarnings, err
 }
 
+// WatchObjects starts watching all objects of the given GVK in the given namespace that match the labels. The watch
+// initially reports all existing objects as added. The caller must call Stop() on the returned watch.
+func (k *K8sCluster) WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, namespace string, labels map[string]string) (watch.Interface, []ApiWarning, error) {
+	var result watch.Interface
+
+	apiWarnings, err := k.clients.withDynamicClientForGVK(k.Resources, gvk, namespace, func(r dynamic.ResourceInterface) error {
+		o := v1.ListOptions{
+			LabelSelector: k.buildLabelSelector(labels),
+		}
+		x, err := r.Watch(ctx, o)
+		if err != nil {
+			return err
+		}
+		result = x
+		return nil
+	})
+	return result, apiWarnings, err
+}
+
 func (k *K8sCluster) ListAllObjects(verbs []string, namespace string, labels map[string]string) ([]*uo.UnstructuredObject, map[schema.GroupVersionKind][]ApiWarning, error) {
 	var ret []*uo.UnstructuredObject
 	var errs []error
@@ -233,6 +254,13 @@ type DeleteOptions struct {
 	ForceDryRun         bool
 	NoWait              bool
 	IgnoreNotFoundError bool
+
+	// StuckTimeout is the time after which waiting for an object that is blocked by finalizers is aborted with a
+	// StuckDeletionError. Zero means to wait forever.
+	StuckTimeout time.Duration
+	// RemoveFinalizersAfter is the time after which finalizers of an object that is blocked by finalizers are
+	// forcefully removed. Zero means to never remove finalizers.
+	RemoveFinalizersAfter time.Duration
 }
 
 func (k *K8sCluster) DeleteSingleObject(ref k8s.ObjectRef, options DeleteOptions) ([]ApiWarning, error) {
@@ -261,7 +289,8 @@ func (k *K8sCluster) DeleteSingleObject(ref k8s.ObjectRef, options DeleteOptions
 	}
 
 	if !dryRun && !options.NoWait {
-		err = k.waitForDeletedObject(ref)
+		apiWarnings2, err := k.waitForDeletedObject(ref, options)
+		apiWarnings = append(apiWarnings, apiWarnings2...)
 		if err != nil {
 			return apiWarnings, err
 		}
@@ -269,25 +298,51 @@ func (k *K8sCluster) DeleteSingleObject(ref k8s.ObjectRef, options DeleteOptions
 	return apiWarnings, nil
 }
 
-func (k *K8sCluster) waitForDeletedObject(ref k8s.ObjectRef) error {
+func (k *K8sCluster) waitForDeletedObject(ref k8s.ObjectRef, options DeleteOptions) ([]ApiWarning, error) {
+	var apiWarnings []ApiWarning
+	startTime := time.Now()
+	removedFinalizers := false
 	for true {
-		_, _, err := k.GetSingleObject(ref)
+		o, _, err := k.GetSingleObject(ref)
 
 		if err != nil {
 			if errors.IsNotFound(err) {
-				return nil
+				return apiWarnings, nil
+			}
+			return apiWarnings, err
+		}
+
+		finalizers := o.GetK8sFinalizers()
+		if !o.GetK8sDeletionTime().IsZero() && len(finalizers) != 0 {
+			stuckTime := time.Now().Sub(startTime)
+			if options.RemoveFinalizersAfter != 0 && stuckTime >= options.RemoveFinalizersAfter && !removedFinalizers {
+				w, err := k.removeFinalizers(ref)
+				apiWarnings = append(apiWarnings, w...)
+				if err != nil && !errors.IsNotFound(err) {
+					return apiWarnings, err
+				}
+				removedFinalizers = true
+				apiWarnings = append(apiWarnings, ApiWarning{
+					Text: fmt.Sprintf("forcefully removed finalizers %s after being stuck in deletion for %s", strings.Join(finalizers, ", "), stuckTime.Round(time.Second).String()),
+				})
+			} else if options.RemoveFinalizersAfter == 0 && options.StuckTimeout != 0 && stuckTime >= options.StuckTimeout {
+				return apiWarnings, &StuckDeletionError{
+					Ref:        ref,
+					Duration:   stuckTime,
+					Finalizers: finalizers,
+					Managers:   getFinalizerManagers(o),
+				}
 			}
-			return err
 		}
 
 		select {
 		case <-time.After(500 * time.Millisecond):
 			continue
 		case <-k.ctx.Done():
-			return fmt.Errorf("failed waiting for deletion of %s: %w", ref.String(), k.ctx.Err())
+			return apiWarnings, fmt.Errorf("failed waiting for deletion of %s: %w", ref.String(), k.ctx.Err())
 		}
 	}
-	return nil
+	return apiWarnings, nil
 }
 
 func (k *K8sCluster) FixObjectForPatch(o *uo.UnstructuredObject) *uo.UnstructuredObject {
@@ -512,6 +567,24 @@ func (k *K8sCluster) ProxyGet(scheme, namespace, name, port, path string, params
 	return ret.Stream(k.ctx)
 }
 
+// GetPodLogs returns the last tailLines lines of the logs of the given container, limited to limitBytes bytes
+func (k *K8sCluster) GetPodLogs(namespace string, name string, container string, tailLines int64, limitBytes int64) (string, []ApiWarning, error) {
+	var ret []byte
+	apiWarnings, err := k.clients.withClientFromPool(func(p *parallelClientEntry) error {
+		var err error
+		ret, err = p.corev1.Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+			Container:  container,
+			TailLines:  &tailLines,
+			LimitBytes: &limitBytes,
+		}).DoRaw(k.ctx)
+		return err
+	})
+	if err != nil {
+		return "", apiWarnings, err
+	}
+	return string(ret), apiWarnings, nil
+}
+
 func (k *K8sCluster) ToRESTConfig() (*rest.Config, error) {
 	return k.clientFactory.RESTConfig(), nil
 }
@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// GetOpenAPISchemaForGVK returns the fully resolved OpenAPI v3 schema for the given GVK, as published by the API
+// server. This works for built-in types as well, in contrast to GetSchemaForGVK which only works for CRDs.
+// Returns nil if the API server does not publish OpenAPI v3 schemas or the GVK is unknown.
+func (k *k8sResources) GetOpenAPISchemaForGVK(gvk schema.GroupVersionKind) (*uo.UnstructuredObject, error) {
+	k.openapiMutex.Lock()
+	s, ok := k.openapiSchemas[gvk]
+	k.openapiMutex.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	doc, err := k.getOpenAPIDocument(gvk.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if doc != nil {
+		schemas, _, _ := doc.GetNestedObject("components", "schemas")
+		if schemas != nil {
+			for _, x := range schemas.Object {
+				m, ok := x.(map[string]interface{})
+				if !ok || !schemaHasGVK(m, gvk) {
+					continue
+				}
+				r := resolveOpenAPISchema(schemas.Object, m, map[string]bool{})
+				s = uo.FromMap(r.(map[string]interface{}))
+				break
+			}
+		}
+	}
+
+	k.openapiMutex.Lock()
+	defer k.openapiMutex.Unlock()
+	if s2, ok := k.openapiSchemas[gvk]; ok {
+		// another goroutine was faster
+		return s2, nil
+	}
+	k.openapiSchemas[gvk] = s
+	return s, nil
+}
+
+// getOpenAPIDocument fetches (and caches) the OpenAPI document of the given GroupVersion. Only fetches of the same
+// GroupVersion are serialized, so that parallel lookups for different GroupVersions don't block each other.
+func (k *k8sResources) getOpenAPIDocument(gv schema.GroupVersion) (*uo.UnstructuredObject, error) {
+	k.openapiMutex.Lock()
+	gvMutex, ok := k.openapiGvMutexes[gv]
+	if !ok {
+		gvMutex = &sync.Mutex{}
+		k.openapiGvMutexes[gv] = gvMutex
+	}
+	k.openapiMutex.Unlock()
+
+	gvMutex.Lock()
+	defer gvMutex.Unlock()
+
+	k.openapiMutex.Lock()
+	doc, ok := k.openapiDocs[gv]
+	k.openapiMutex.Unlock()
+	if ok {
+		return doc, nil
+	}
+
+	doc, err := k.fetchOpenAPIDocument(gv)
+	if err != nil {
+		return nil, err
+	}
+
+	k.openapiMutex.Lock()
+	k.openapiDocs[gv] = doc
+	k.openapiMutex.Unlock()
+	return doc, nil
+}
+
+func (k *k8sResources) fetchOpenAPIDocument(gv schema.GroupVersion) (*uo.UnstructuredObject, error) {
+	rc := k.discovery.RESTClient()
+	if rc == nil || reflect.ValueOf(rc).IsNil() {
+		return nil, nil
+	}
+
+	p := fmt.Sprintf("/openapi/v3/apis/%s/%s", gv.Group, gv.Version)
+	if gv.Group == "" {
+		p = fmt.Sprintf("/openapi/v3/api/%s", gv.Version)
+	}
+
+	data, err := rc.Get().AbsPath(p).SetHeader("Accept", "application/json").Do(k.ctx).Raw()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve OpenAPI schema for %s: %w", gv.String(), err)
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI schema for %s: %w", gv.String(), err)
+	}
+	return uo.FromMap(m), nil
+}
+
+func schemaHasGVK(s map[string]interface{}, gvk schema.GroupVersionKind) bool {
+	l, ok := s["x-kubernetes-group-version-kind"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, x := range l {
+		m, ok := x.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveOpenAPISchema returns a copy of the given schema with all references and allOf lists inlined. Recursive
+// references are cut off and replaced with empty schemas.
+func resolveOpenAPISchema(schemas map[string]interface{}, s interface{}, stack map[string]bool) interface{} {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return s
+	}
+
+	ret := map[string]interface{}{}
+
+	if ref, ok := m["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		target, ok := schemas[name]
+		if ok && !stack[name] {
+			stack[name] = true
+			r := resolveOpenAPISchema(schemas, target, stack)
+			delete(stack, name)
+			if rm, ok := r.(map[string]interface{}); ok {
+				for k, v := range rm {
+					ret[k] = v
+				}
+			}
+		}
+	}
+	if allOf, ok := m["allOf"].([]interface{}); ok {
+		for _, x := range allOf {
+			r := resolveOpenAPISchema(schemas, x, stack)
+			if rm, ok := r.(map[string]interface{}); ok {
+				for k, v := range rm {
+					ret[k] = v
+				}
+			}
+		}
+	}
+
+	for k, v := range m {
+		switch k {
+		case "$ref", "allOf":
+			continue
+		case "properties":
+			props, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			newProps := map[string]interface{}{}
+			for pn, ps := range props {
+				newProps[pn] = resolveOpenAPISchema(schemas, ps, stack)
+			}
+			ret[k] = newProps
+		case "items", "additionalProperties":
+			ret[k] = resolveOpenAPISchema(schemas, v, stack)
+		default:
+			ret[k] = v
+		}
+	}
+	return ret
+}
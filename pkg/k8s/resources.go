@@ -32,6 +32,12 @@ type k8sResources struct {
 	preferredResources map[schema.GroupKind]v1.APIResource
 	crds               map[schema.GroupKind]*uo.UnstructuredObject
 	mutex              sync.Mutex
+
+	openapiDocs    map[schema.GroupVersion]*uo.UnstructuredObject
+	openapiSchemas map[schema.GroupVersionKind]*uo.UnstructuredObject
+	openapiMutex   sync.Mutex
+	// openapiGvMutexes serializes fetching of the OpenAPI document per GroupVersion
+	openapiGvMutexes map[schema.GroupVersion]*sync.Mutex
 }
 
 func newK8sResources(ctx context.Context, clientFactory ClientFactory) (*k8sResources, error) {
@@ -41,6 +47,9 @@ func newK8sResources(ctx context.Context, clientFactory ClientFactory) (*k8sReso
 		preferredResources: map[schema.GroupKind]v1.APIResource{},
 		crds:               map[schema.GroupKind]*uo.UnstructuredObject{},
 		mutex:              sync.Mutex{},
+		openapiDocs:        map[schema.GroupVersion]*uo.UnstructuredObject{},
+		openapiSchemas:     map[schema.GroupVersionKind]*uo.UnstructuredObject{},
+		openapiGvMutexes:   map[schema.GroupVersion]*sync.Mutex{},
 	}
 
 	var err error
@@ -0,0 +1,37 @@
+package kluctl_project
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"os"
+	"path/filepath"
+)
+
+// LoadImageVerificationKeys returns the configured image verification keys with all public keys being loaded into
+// PublicKey. Key files are resolved relative to the project directory.
+func (c *LoadedKluctlProject) LoadImageVerificationKeys() ([]types.ImageVerificationKey, error) {
+	if c.Config.ImageVerification == nil {
+		return nil, nil
+	}
+
+	var ret []types.ImageVerificationKey
+	for _, k := range c.Config.ImageVerification.Keys {
+		if k.PublicKeyFile != nil {
+			p := filepath.Join(c.ProjectDir, *k.PublicKeyFile)
+			err := utils.CheckInDir(c.projectRootDir, p)
+			if err != nil {
+				return nil, err
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read public key for image pattern %s: %w", k.Pattern, err)
+			}
+			s := string(b)
+			k.PublicKey = &s
+			k.PublicKeyFile = nil
+		}
+		ret = append(ret, k)
+	}
+	return ret, nil
+}
@@ -63,6 +63,13 @@ func (p *LoadedKluctlProject) NewTargetContext(ctx context.Context, params Targe
 	}
 
 	params.Images.PrependFixedImages(target.Images)
+	if target.PinDigests != nil && *target.PinDigests {
+		params.Images.SetPinDigests(true)
+	}
+	err = params.Images.SetImageRewrites(target.ImageRewrites)
+	if err != nil {
+		return nil, err
+	}
 
 	clientConfig, clusterContext, err := p.loadK8sConfig(target, params.OfflineK8s)
 	if err != nil {
@@ -106,6 +106,39 @@ func (rh *RegistryHelper) ListImageTags(image string) ([]string, error) {
 	return ret, err
 }
 
+// GetImageDigest resolves the given image reference (e.g. 'image:tag') to the digest of its manifest
+func (rh *RegistryHelper) GetImageDigest(image string) (string, error) {
+	var nameOpts []name.Option
+	ref, err := name.ParseReference(image, nameOpts...)
+	if err != nil {
+		return "", err
+	}
+	registry := ref.Context().RegistryStr()
+
+	if rh.isInsecureRegistry(registry) {
+		nameOpts = append(nameOpts, name.Insecure)
+		ref, err = name.ParseReference(image, nameOpts...)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	remoteOpts, err := rh.buildRemoteOptions(registry)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := remote.Head(ref, remoteOpts...)
+	if e, ok := err.(*transport.Error); ok && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden) {
+		return "", fmt.Errorf("failed to authenticate against image registry %s, "+
+			"please make sure that you provided credentials, e.g. via 'docker login' or via environment variables: %w", registry, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
 func (rh *RegistryHelper) AddAuthEntry(e AuthEntry) {
 	rh.authEntries = append(rh.authEntries, e)
 }
@@ -367,7 +400,7 @@ func (rh *RegistryHelper) writeCachedResponse(key string, data []byte) {
 }
 
 func (rh *RegistryHelper) RoundTripCached(req *http.Request, extraKey string, onNew func(res *http.Response) error) (*http.Response, error) {
-	key := fmt.Sprintf("%s\n%s\n%s\n", req.Host, req.URL.Path, extraKey)
+	key := fmt.Sprintf("%s\n%s\n%s\n%s\n", req.URL.Scheme, req.Host, req.URL.Path, extraKey)
 	key = utils.Sha256String(key)
 
 	isNew := false
@@ -0,0 +1,160 @@
+package registries
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"io"
+	"strings"
+)
+
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// cosignPayload is the "simple signing" payload which is signed by cosign
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// ParsePublicKey parses a PEM encoded public key, as generated by 'cosign generate-key-pair'
+func ParsePublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM encoded public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+func verifySignature(key crypto.PublicKey, payload []byte, sig []byte) bool {
+	h := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, h[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	}
+	return false
+}
+
+func (rh *RegistryHelper) buildRemoteOptions(registry string) ([]remote.Option, error) {
+	t, err := rh.buildTransport(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return []remote.Option{
+		remote.WithAuthFromKeychain(rh),
+		remote.WithTransport(rh),
+		remote.WithContext(context.WithValue(rh.ctx, transportKey, t)),
+	}, nil
+}
+
+// VerifyImageSignature verifies that the given image has a cosign compatible signature which can be verified by at
+// least one of the given public keys. Signatures are expected to be stored in the same repository as the image,
+// using the 'sha256-<digest>.sig' tag convention of cosign.
+func (rh *RegistryHelper) VerifyImageSignature(image string, keys []crypto.PublicKey) error {
+	var digest string
+	if i := strings.Index(image, "@"); i != -1 {
+		digest = image[i+1:]
+	} else {
+		var err error
+		digest, err = rh.GetImageDigest(image)
+		if err != nil {
+			return err
+		}
+	}
+
+	var nameOpts []name.Option
+	ref, err := name.ParseReference(image, nameOpts...)
+	if err != nil {
+		return err
+	}
+	registry := ref.Context().RegistryStr()
+	if rh.isInsecureRegistry(registry) {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	sigRef, err := name.NewTag(fmt.Sprintf("%s:%s", ref.Context().Name(), sigTag), nameOpts...)
+	if err != nil {
+		return err
+	}
+
+	remoteOpts, err := rh.buildRemoteOptions(registry)
+	if err != nil {
+		return err
+	}
+
+	img, err := remote.Image(sigRef, remoteOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve signature %s: %w", sigRef.String(), err)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range m.Layers {
+		sigB64, ok := l.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(sigB64)
+		if err != nil {
+			continue
+		}
+
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return err
+		}
+		r, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+		payload, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+
+		var p cosignPayload
+		err = json.Unmarshal(payload, &p)
+		if err != nil || p.Critical.Image.DockerManifestDigest != digest {
+			continue
+		}
+
+		for _, key := range keys {
+			if verifySignature(key, payload, sig) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("no valid signature found for %s@%s", ref.Context().Name(), digest)
+}
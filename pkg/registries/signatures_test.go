@@ -0,0 +1,121 @@
+package registries
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func pushTestImage(t *testing.T, image string) string {
+	img, err := random.Image(1024, 1)
+	assert.NoError(t, err)
+	ref, err := name.ParseReference(image)
+	assert.NoError(t, err)
+	err = remote.Write(ref, img)
+	assert.NoError(t, err)
+	d, err := img.Digest()
+	assert.NoError(t, err)
+	return d.String()
+}
+
+func pushTestSignature(t *testing.T, repo string, digest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, repo, digest))
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	assert.NoError(t, err)
+
+	l := static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json")
+	img, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), mutate.Addendum{
+		Layer: l,
+		Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	assert.NoError(t, err)
+
+	ref, err := name.ParseReference(fmt.Sprintf("%s:%s.sig", repo, strings.Replace(digest, ":", "-", 1)))
+	assert.NoError(t, err)
+	err = remote.Write(ref, img)
+	assert.NoError(t, err)
+}
+
+func generateTestKey(t *testing.T) (*ecdsa.PrivateKey, crypto.PublicKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	pub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+	assert.NoError(t, err)
+	return key, pub
+}
+
+func TestVerifyImageSignature(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	key, pub := generateTestKey(t)
+	_, otherPub := generateTestKey(t)
+
+	signedRepo := fmt.Sprintf("%s/signed", u.Host)
+	unsignedRepo := fmt.Sprintf("%s/unsigned", u.Host)
+
+	signedDigest := pushTestImage(t, signedRepo+":1.0.0")
+	pushTestSignature(t, signedRepo, signedDigest, key)
+	pushTestImage(t, unsignedRepo+":1.0.0")
+
+	rh := NewRegistryHelper(context.Background())
+	rh.AddAuthEntry(AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+
+	assert.NoError(t, rh.VerifyImageSignature(signedRepo+":1.0.0", []crypto.PublicKey{pub}))
+	assert.NoError(t, rh.VerifyImageSignature(signedRepo+":1.0.0@"+signedDigest, []crypto.PublicKey{otherPub, pub}))
+	assert.Error(t, rh.VerifyImageSignature(signedRepo+":1.0.0", []crypto.PublicKey{otherPub}))
+	assert.Error(t, rh.VerifyImageSignature(unsignedRepo+":1.0.0", []crypto.PublicKey{pub}))
+}
+
+func TestGetImageDigest(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer s.Close()
+	u, _ := url.Parse(s.URL)
+
+	repo := fmt.Sprintf("%s/app", u.Host)
+	digest := pushTestImage(t, repo+":1.0.0")
+
+	rh := NewRegistryHelper(context.Background())
+	rh.AddAuthEntry(AuthEntry{
+		Registry: u.Host,
+		Insecure: true,
+	})
+
+	d, err := rh.GetImageDigest(repo + ":1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, digest, d)
+
+	_, err = rh.GetImageDigest(repo + ":2.0.0")
+	assert.Error(t, err)
+}
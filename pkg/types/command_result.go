@@ -13,32 +13,109 @@ type Change struct {
 	UnifiedDiff string      `yaml:"unifiedDiff,omitempty"`
 }
 
+type FieldOwner struct {
+	Manager   string `yaml:"manager"`
+	Operation string `yaml:"operation,omitempty"`
+}
+
+type FieldOwnership struct {
+	JsonPath string       `yaml:"jsonPath"`
+	Owners   []FieldOwner `yaml:"owners,omitempty"`
+	// Conflict is true if the field is owned by at least one field manager that kluctl is not allowed to overwrite
+	Conflict bool `yaml:"conflict,omitempty"`
+}
+
+type ObjectOwnership struct {
+	Ref    k8s.ObjectRef    `yaml:"ref"`
+	Fields []FieldOwnership `yaml:"fields,omitempty"`
+}
+
 type ChangedObject struct {
 	Ref       k8s.ObjectRef          `yaml:"ref"`
 	NewObject *uo.UnstructuredObject `yaml:"newObject,omitempty"`
 	OldObject *uo.UnstructuredObject `yaml:"oldObject,omitempty"`
 	Changes   []Change               `yaml:"changes,omitempty"`
+	Ownership []FieldOwnership       `yaml:"ownership,omitempty"`
+
+	// RequiresRecreation is true when the changes touch immutable fields, meaning that the object must be deleted and
+	// re-created to apply the changes
+	RequiresRecreation bool     `yaml:"requiresRecreation,omitempty"`
+	ImmutableChanges   []string `yaml:"immutableChanges,omitempty"`
 }
 
 type RefAndObject struct {
 	Ref    k8s.ObjectRef          `yaml:"ref"`
 	Object *uo.UnstructuredObject `yaml:"object,omitempty"`
+	// Logs contains the (truncated) logs of hook Pods, only set for hooks when hook logs are enabled
+	Logs string `yaml:"logs,omitempty"`
 }
 
 type DeploymentError struct {
 	Ref   k8s.ObjectRef `yaml:"ref"`
 	Error string        `yaml:"error"`
+	// Logs contains the (truncated) logs of failed hook Pods
+	Logs string `yaml:"logs,omitempty"`
+	// Events contains recent warning events (one per line) of objects that failed to become ready, including events of
+	// owned ReplicaSets and Pods
+	Events string `yaml:"events,omitempty"`
+}
+
+type RolledBackObject struct {
+	Ref k8s.ObjectRef `yaml:"ref"`
+	// Action is either "restored" (the object was restored to its previous state) or "deleted" (the object did
+	// not exist before)
+	Action string `yaml:"action"`
+}
+
+const (
+	HealthHealthy     = "Healthy"
+	HealthProgressing = "Progressing"
+	HealthMissing     = "Missing"
+	HealthDegraded    = "Degraded"
+)
+
+type ObjectHealth struct {
+	Ref     k8s.ObjectRef `yaml:"ref"`
+	Health  string        `yaml:"health"`
+	Message string        `yaml:"message,omitempty"`
+}
+
+// DeploymentItemHealth is the aggregated health of all objects of a deployment item. Only objects which are not
+// healthy are listed in Objects.
+type DeploymentItemHealth struct {
+	Dir     string         `yaml:"dir"`
+	Health  string         `yaml:"health"`
+	Objects []ObjectHealth `yaml:"objects,omitempty"`
 }
 
 type CommandResult struct {
-	NewObjects     []*RefAndObject   `yaml:"newObjects,omitempty"`
+	NewObjects        []*RefAndObject        `yaml:"newObjects,omitempty"`
+	ChangedObjects    []*ChangedObject       `yaml:"changedObjects,omitempty"`
+	HookObjects       []*RefAndObject        `yaml:"hookObjects,omitempty"`
+	OrphanObjects     []k8s.ObjectRef        `yaml:"orphanObjects,omitempty"`
+	DeletedObjects    []k8s.ObjectRef        `yaml:"deletedObjects,omitempty"`
+	ProtectedObjects  []k8s.ObjectRef        `yaml:"protectedObjects,omitempty"`
+	RolledBackObjects []RolledBackObject     `yaml:"rolledBackObjects,omitempty"`
+	Errors            []DeploymentError      `yaml:"errors,omitempty"`
+	Warnings          []DeploymentError      `yaml:"warnings,omitempty"`
+	SeenImages        []FixedImage           `yaml:"seenImages,omitempty"`
+	Health            []DeploymentItemHealth `yaml:"health,omitempty"`
+}
+
+type DriftResult struct {
+	Drifted        bool              `yaml:"drifted"`
+	MissingObjects []k8s.ObjectRef   `yaml:"missingObjects,omitempty"`
 	ChangedObjects []*ChangedObject  `yaml:"changedObjects,omitempty"`
-	HookObjects    []*RefAndObject   `yaml:"hookObjects,omitempty"`
-	OrphanObjects  []k8s.ObjectRef   `yaml:"orphanObjects,omitempty"`
 	DeletedObjects []k8s.ObjectRef   `yaml:"deletedObjects,omitempty"`
+	OrphanObjects  []k8s.ObjectRef   `yaml:"orphanObjects,omitempty"`
 	Errors         []DeploymentError `yaml:"errors,omitempty"`
 	Warnings       []DeploymentError `yaml:"warnings,omitempty"`
-	SeenImages     []FixedImage      `yaml:"seenImages,omitempty"`
+}
+
+type OwnershipResult struct {
+	Objects  []ObjectOwnership `yaml:"objects,omitempty"`
+	Errors   []DeploymentError `yaml:"errors,omitempty"`
+	Warnings []DeploymentError `yaml:"warnings,omitempty"`
 }
 
 type ValidateResultEntry struct {
@@ -48,8 +125,46 @@ type ValidateResultEntry struct {
 }
 
 type ValidateResult struct {
-	Ready    bool                  `yaml:"ready"`
-	Warnings []DeploymentError     `yaml:"warnings,omitempty"`
-	Errors   []DeploymentError     `yaml:"errors,omitempty"`
-	Results  []ValidateResultEntry `yaml:"results,omitempty"`
+	Ready    bool                   `yaml:"ready"`
+	Warnings []DeploymentError      `yaml:"warnings,omitempty"`
+	Errors   []DeploymentError      `yaml:"errors,omitempty"`
+	Results  []ValidateResultEntry  `yaml:"results,omitempty"`
+	Health   []DeploymentItemHealth `yaml:"health,omitempty"`
+}
+
+// ValidateTransition describes a change of the health of an object while watching it
+type ValidateTransition struct {
+	Ref       k8s.ObjectRef `yaml:"ref"`
+	OldHealth string        `yaml:"oldHealth"`
+	NewHealth string        `yaml:"newHealth"`
+	Message   string        `yaml:"message,omitempty"`
+	// Events contains recent warning events of the object, only set when the object is not healthy
+	Events []string `yaml:"events,omitempty"`
+}
+
+// ObjectStatus describes the live state of a single object found in the cluster
+type ObjectStatus struct {
+	Ref     k8s.ObjectRef `yaml:"ref"`
+	Health  string        `yaml:"health"`
+	Message string        `yaml:"message,omitempty"`
+	// Images contains the images that are actually running for this object, e.g. in the Pods owned by a Deployment
+	Images []string `yaml:"images,omitempty"`
+	// LastApplied is the last time kluctl applied this object (RFC3339), as recorded in the managedFields
+	LastApplied string `yaml:"lastApplied,omitempty"`
+}
+
+// DeploymentItemStatus describes the live state of a deployment item, based on the objects found in the cluster
+type DeploymentItemStatus struct {
+	Dir         string         `yaml:"dir"`
+	Health      string         `yaml:"health"`
+	Images      []string       `yaml:"images,omitempty"`
+	LastApplied string         `yaml:"lastApplied,omitempty"`
+	Objects     []ObjectStatus `yaml:"objects,omitempty"`
+}
+
+type StatusResult struct {
+	Items         []DeploymentItemStatus `yaml:"items,omitempty"`
+	OrphanObjects []k8s.ObjectRef        `yaml:"orphanObjects,omitempty"`
+	Errors        []DeploymentError      `yaml:"errors,omitempty"`
+	Warnings      []DeploymentError      `yaml:"warnings,omitempty"`
 }
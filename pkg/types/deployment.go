@@ -1,7 +1,9 @@
 package types
 
 import (
+	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 )
 
@@ -17,6 +19,43 @@ type DeploymentItemConfig struct {
 	OnlyRender       bool                     `yaml:"onlyRender,omitempty"`
 	AlwaysDeploy     bool                     `yaml:"alwaysDeploy,omitempty"`
 	DeleteObjects    []DeleteObjectItemConfig `yaml:"deleteObjects,omitempty"`
+	Rollout          *RolloutConfig           `yaml:"rollout,omitempty"`
+	OnFailure        string                   `yaml:"onFailure,omitempty"`
+	ExternalHooks    []*ExternalHookConfig    `yaml:"externalHooks,omitempty"`
+}
+
+// ExternalHookConfig describes a hook that is not a Kubernetes object but either a local command or an HTTP request
+type ExternalHookConfig struct {
+	Name    string                     `yaml:"name" validate:"required"`
+	Hooks   []string                   `yaml:"hooks" validate:"required"`
+	Weight  int                        `yaml:"weight,omitempty"`
+	Timeout string                     `yaml:"timeout,omitempty"`
+	Command *ExternalHookCommandConfig `yaml:"command,omitempty"`
+	Http    *ExternalHookHttpConfig    `yaml:"http,omitempty"`
+}
+
+type ExternalHookCommandConfig struct {
+	Command []string          `yaml:"command" validate:"required,min=1"`
+	Env     map[string]string `yaml:"env,omitempty"`
+}
+
+type ExternalHookHttpConfig struct {
+	Url     string            `yaml:"url" validate:"required"`
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+}
+
+func ValidateExternalHookConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(ExternalHookConfig)
+	if (s.Command == nil) == (s.Http == nil) {
+		sl.ReportError(s, "self", "self", "exactly one of command and http must be set", "")
+	}
+}
+
+type RolloutConfig struct {
+	// Steps is a list of percentages of replicas which are updated per step. A final step with 100% is always added.
+	Steps []int `yaml:"steps,omitempty"`
 }
 
 func ValidateDeploymentItemConfig(sl validator.StructLevel) {
@@ -37,6 +76,70 @@ func ValidateDeploymentItemConfig(sl validator.StructLevel) {
 	if s.Path == nil && s.WaitReadiness {
 		sl.ReportError(s, "waitReadiness", "WaitReadiness", "only kustomize deployments are allowed to have waitReadiness set", "")
 	}
+	if s.OnFailure != "" && s.OnFailure != "rollback" {
+		sl.ReportError(s, "onFailure", "OnFailure", "onFailure must be 'rollback' or empty", "")
+	}
+	if s.Path == nil && s.OnFailure != "" {
+		sl.ReportError(s, "onFailure", "OnFailure", "only kustomize deployments are allowed to have onFailure set", "")
+	}
+	if s.Path == nil && s.Rollout != nil {
+		sl.ReportError(s, "rollout", "Rollout", "only kustomize deployments are allowed to have rollout set", "")
+	}
+	if s.Rollout != nil {
+		for i, p := range s.Rollout.Steps {
+			if p <= 0 || p > 100 || (i > 0 && p <= s.Rollout.Steps[i-1]) {
+				sl.ReportError(s, "rollout", "Rollout", "rollout steps must be ascending percentages between 1 and 100", "")
+				break
+			}
+		}
+	}
+}
+
+type ProtectedKindConfig struct {
+	Group *string `yaml:"group,omitempty"`
+	Kind  string  `yaml:"kind" validate:"required"`
+}
+
+// ReadinessRuleConfig defines readiness for all objects of the given kind. Conditions are JSONPath expressions, which
+// are considered to be true when they match at least one value that is not false, null or an empty string.
+type ReadinessRuleConfig struct {
+	Group   *string `yaml:"group,omitempty"`
+	Version *string `yaml:"version,omitempty"`
+	Kind    string  `yaml:"kind" validate:"required"`
+
+	Ready      string `yaml:"ready" validate:"required"`
+	Failed     string `yaml:"failed,omitempty"`
+	InProgress string `yaml:"inProgress,omitempty"`
+	Message    string `yaml:"message,omitempty"`
+}
+
+func ValidateReadinessRuleConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(ReadinessRuleConfig)
+	check := func(name string, p string) {
+		if p == "" {
+			return
+		}
+		if _, err := uo.NewMyJsonPath(p); err != nil {
+			sl.ReportError(s, name, name, fmt.Sprintf("invalid JSONPath: %s", err.Error()), "")
+		}
+	}
+	check("ready", s.Ready)
+	check("failed", s.Failed)
+	check("inProgress", s.InProgress)
+	check("message", s.Message)
+}
+
+type DeleteOrderConfig struct {
+	// Phases is a list of phases, each consisting of a list of kinds, API groups or resource names
+	Phases     [][]string `yaml:"phases,omitempty"`
+	Namespaces string     `yaml:"namespaces,omitempty"`
+}
+
+func ValidateDeleteOrderConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(DeleteOrderConfig)
+	if s.Namespaces != "" && s.Namespaces != "first" && s.Namespaces != "last" {
+		sl.ReportError(s, "namespaces", "namespaces", "namespaces must be 'first' or 'last'", "")
+	}
 }
 
 type DeleteObjectItemConfig struct {
@@ -88,6 +191,58 @@ type IgnoreForDiffItemConfig struct {
 	Namespace *string            `yaml:"namespace,omitempty"`
 }
 
+type SortListByConfig struct {
+	FieldPath SingleStringOrList `yaml:"fieldPath" validate:"required"`
+	Key       string             `yaml:"key" validate:"required"`
+}
+
+type DefaultValueConfig struct {
+	FieldPath SingleStringOrList `yaml:"fieldPath" validate:"required"`
+	Value     interface{}        `yaml:"value"`
+}
+
+type RenameFieldConfig struct {
+	FieldPath SingleStringOrList `yaml:"fieldPath" validate:"required"`
+	NewName   string             `yaml:"newName" validate:"required"`
+}
+
+type NormalizeForDiffItemConfig struct {
+	Group     *string `yaml:"group,omitempty"`
+	Kind      *string `yaml:"kind,omitempty"`
+	Name      *string `yaml:"name,omitempty"`
+	Namespace *string `yaml:"namespace,omitempty"`
+
+	SortListBy      []*SortListByConfig   `yaml:"sortListBy,omitempty"`
+	Quantities      SingleStringOrList    `yaml:"quantities,omitempty"`
+	Durations       SingleStringOrList    `yaml:"durations,omitempty"`
+	Defaults        []*DefaultValueConfig `yaml:"defaults,omitempty"`
+	Renames         []*RenameFieldConfig  `yaml:"renames,omitempty"`
+	PodTemplatePath SingleStringOrList    `yaml:"podTemplatePath,omitempty"`
+}
+
+func ValidateNormalizeForDiffItemConfig(sl validator.StructLevel) {
+	s := sl.Current().Interface().(NormalizeForDiffItemConfig)
+	check := func(name string, paths []string) {
+		for _, p := range paths {
+			if _, err := uo.NewMyJsonPath(p); err != nil {
+				sl.ReportError(s, name, name, fmt.Sprintf("invalid JSONPath %s: %s", p, err.Error()), "")
+			}
+		}
+	}
+	for _, x := range s.SortListBy {
+		check("sortListBy", x.FieldPath)
+	}
+	check("quantities", s.Quantities)
+	check("durations", s.Durations)
+	for _, x := range s.Defaults {
+		check("defaults", x.FieldPath)
+	}
+	for _, x := range s.Renames {
+		check("renames", x.FieldPath)
+	}
+	check("podTemplatePath", s.PodTemplatePath)
+}
+
 type DeploymentProjectConfig struct {
 	Args          []*DeploymentArg     `yaml:"args,omitempty"`
 	Vars          []*VarsSource        `yaml:"vars,omitempty"`
@@ -99,11 +254,21 @@ type DeploymentProjectConfig struct {
 	OverrideNamespace *string           `yaml:"overrideNamespace,omitempty"`
 	Tags              []string          `yaml:"tags,omitempty"`
 
-	IgnoreForDiff    []*IgnoreForDiffItemConfig `yaml:"ignoreForDiff,omitempty"`
-	TemplateExcludes []string                   `yaml:"templateExcludes,omitempty"`
+	IgnoreForDiff    []*IgnoreForDiffItemConfig    `yaml:"ignoreForDiff,omitempty"`
+	NormalizeForDiff []*NormalizeForDiffItemConfig `yaml:"normalizeForDiff,omitempty"`
+	TemplateExcludes []string                      `yaml:"templateExcludes,omitempty"`
+
+	ProtectedKinds []*ProtectedKindConfig `yaml:"protectedKinds,omitempty"`
+	DeleteOrder    *DeleteOrderConfig     `yaml:"deleteOrder,omitempty"`
+
+	ReadinessRules []*ReadinessRuleConfig `yaml:"readinessRules,omitempty"`
 }
 
 func init() {
 	yaml.Validator.RegisterStructValidation(ValidateDeploymentItemConfig, DeploymentItemConfig{})
 	yaml.Validator.RegisterStructValidation(ValidateDeleteObjectItemConfig, DeleteObjectItemConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateDeleteOrderConfig, DeleteOrderConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateExternalHookConfig, ExternalHookConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateReadinessRuleConfig, ReadinessRuleConfig{})
+	yaml.Validator.RegisterStructValidation(ValidateNormalizeForDiffItemConfig, NormalizeForDiffItemConfig{})
 }
@@ -0,0 +1,15 @@
+package types
+
+type ImageCatalogEntry struct {
+	Image string   `yaml:"image" validate:"required"`
+	Tags  []string `yaml:"tags,omitempty"`
+
+	// Digests maps tags to manifest digests. It is only filled when digest pinning is enabled.
+	Digests map[string]string `yaml:"digests,omitempty"`
+}
+
+// ImageCatalog is a snapshot of the tags available in image registries. It allows to resolve 'latest_version'
+// filters of 'images.get_image(...)' calls without contacting any registry.
+type ImageCatalog struct {
+	Images []ImageCatalogEntry `yaml:"images,omitempty"`
+}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
 )
 
 type DynamicArg struct {
@@ -26,6 +28,23 @@ type SealingConfig struct {
 	CertFile       *string                `yaml:"certFile,omitempty"`
 }
 
+type ImageRewrite struct {
+	// Prefix is replaced with Replacement if an image starts with it. Can't be combined with 'regex'.
+	Prefix *string `yaml:"prefix,omitempty"`
+	// Regex is matched against the full image. Replacement may reference capture groups, e.g. via '$1'.
+	Regex       *string `yaml:"regex,omitempty"`
+	Replacement string  `yaml:"replacement"`
+}
+
+func ValidateImageRewrite(sl validator.StructLevel) {
+	r := sl.Current().Interface().(ImageRewrite)
+	if r.Prefix == nil && r.Regex == nil {
+		sl.ReportError(r, ".", ".", "either prefix or regex must be set", "")
+	} else if r.Prefix != nil && r.Regex != nil {
+		sl.ReportError(r, ".", ".", "only one of prefix or regex can be set", "")
+	}
+}
+
 type Target struct {
 	Name          string                 `yaml:"name" validate:"required"`
 	Context       *string                `yaml:"context,omitempty"`
@@ -34,6 +53,8 @@ type Target struct {
 	TargetConfig  *ExternalTargetConfig  `yaml:"targetConfig,omitempty"`
 	SealingConfig *SealingConfig         `yaml:"sealingConfig,omitempty"`
 	Images        []FixedImage           `yaml:"images,omitempty"`
+	PinDigests    *bool                  `yaml:"pinDigests,omitempty"`
+	ImageRewrites []ImageRewrite         `yaml:"imageRewrites,omitempty"`
 }
 
 type DynamicTarget struct {
@@ -57,7 +78,33 @@ type SecretsConfig struct {
 	SecretSets    []SecretSet                `yaml:"secretSets,omitempty"`
 }
 
+type ImageVerificationKey struct {
+	// Pattern is matched against the image repository (without tag and digest), e.g. 'registry.example.com/my-group/*'
+	Pattern       string  `yaml:"pattern" validate:"required"`
+	PublicKey     *string `yaml:"publicKey,omitempty"`
+	PublicKeyFile *string `yaml:"publicKeyFile,omitempty"`
+}
+
+func ValidateImageVerificationKey(sl validator.StructLevel) {
+	k := sl.Current().Interface().(ImageVerificationKey)
+	if k.PublicKey == nil && k.PublicKeyFile == nil {
+		sl.ReportError(k, ".", ".", "either publicKey or publicKeyFile must be set", "")
+	} else if k.PublicKey != nil && k.PublicKeyFile != nil {
+		sl.ReportError(k, ".", ".", "only one of publicKey or publicKeyFile can be set", "")
+	}
+}
+
+type ImageVerificationConfig struct {
+	Keys []ImageVerificationKey `yaml:"keys,omitempty"`
+}
+
 type KluctlProject struct {
-	Targets       []*Target      `yaml:"targets,omitempty"`
-	SecretsConfig *SecretsConfig `yaml:"secretsConfig,omitempty"`
+	Targets           []*Target                `yaml:"targets,omitempty"`
+	SecretsConfig     *SecretsConfig           `yaml:"secretsConfig,omitempty"`
+	ImageVerification *ImageVerificationConfig `yaml:"imageVerification,omitempty"`
+}
+
+func init() {
+	yaml.Validator.RegisterStructValidation(ValidateImageRewrite, ImageRewrite{})
+	yaml.Validator.RegisterStructValidation(ValidateImageVerificationKey, ImageVerificationKey{})
 }
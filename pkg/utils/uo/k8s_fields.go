@@ -202,6 +202,11 @@ func (uo *UnstructuredObject) SetK8sResourceVersion(rv string) {
 	}
 }
 
+func (uo *UnstructuredObject) GetK8sUid() string {
+	ret, _, _ := uo.GetNestedString("metadata", "uid")
+	return ret
+}
+
 func (uo *UnstructuredObject) GetK8sOwnerReferences() []*UnstructuredObject {
 	ret, _, _ := uo.GetNestedObjectList("metadata", "ownerReferences")
 	return ret
@@ -224,6 +229,23 @@ func (uo *UnstructuredObject) GetK8sCreationTime() time.Time {
 	return t
 }
 
+func (uo *UnstructuredObject) GetK8sDeletionTime() time.Time {
+	v, ok, _ := uo.GetNestedString("metadata", "deletionTimestamp")
+	if !ok {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func (uo *UnstructuredObject) GetK8sFinalizers() []string {
+	ret, _, _ := uo.GetNestedStringList("metadata", "finalizers")
+	return ret
+}
+
 func (ui *UnstructuredObject) getRegexp(r interface{}) *regexp.Regexp {
 	if x, ok := r.(*regexp.Regexp); ok {
 		return x
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+)
+
+// healthOrder defines which health status wins when aggregating the health of multiple objects
+var healthOrder = map[string]int{
+	types.HealthHealthy:     0,
+	types.HealthProgressing: 1,
+	types.HealthMissing:     2,
+	types.HealthDegraded:    3,
+}
+
+// GetObjectHealth determines the health of a single object. A nil object is considered to be missing, objects with
+// validation errors are degraded and objects which are not ready yet are progressing.
+func GetObjectHealth(k *k8s.K8sCluster, ref k8s2.ObjectRef, o *uo.UnstructuredObject, rules *ReadinessRules) types.ObjectHealth {
+	ret := types.ObjectHealth{Ref: ref}
+	if o == nil {
+		ret.Health = types.HealthMissing
+		return ret
+	}
+
+	v := ValidateObject(k, o, false, false, rules)
+	if len(v.Errors) != 0 {
+		ret.Health = types.HealthDegraded
+		ret.Message = v.Errors[0].Error
+	} else if !v.Ready {
+		ret.Health = types.HealthProgressing
+		if len(v.Warnings) != 0 {
+			ret.Message = v.Warnings[0].Error
+		}
+	} else {
+		ret.Health = types.HealthHealthy
+	}
+	return ret
+}
+
+// AggregateHealth returns the worst health found in the given list. An empty list is considered to be healthy.
+func AggregateHealth(l []types.ObjectHealth) string {
+	ret := types.HealthHealthy
+	for _, x := range l {
+		if healthOrder[x.Health] > healthOrder[ret] {
+			ret = x.Health
+		}
+	}
+	return ret
+}
@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildDeployment(readyReplicas int, progressing string, reason string, observedGeneration int) *uo.UnstructuredObject {
+	o := uo.FromStringMust(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d1
+  namespace: default
+  generation: 2
+spec:
+  replicas: 2
+status:
+  replicas: 2
+  conditions:
+  - type: Progressing
+    message: deadline exceeded
+`)
+	_ = o.SetNestedField(int64(readyReplicas), "status", "readyReplicas")
+	_ = o.SetNestedField(int64(observedGeneration), "status", "observedGeneration")
+	_ = o.SetNestedField(progressing, "status", "conditions", 0, "status")
+	_ = o.SetNestedField(reason, "status", "conditions", 0, "reason")
+	return o
+}
+
+func TestGetObjectHealth(t *testing.T) {
+	o := buildDeployment(2, "True", "NewReplicaSetAvailable", 2)
+	h := GetObjectHealth(nil, o.GetK8sRef(), o, nil)
+	assert.Equal(t, types.HealthHealthy, h.Health)
+
+	o = buildDeployment(1, "True", "ReplicaSetUpdated", 2)
+	h = GetObjectHealth(nil, o.GetK8sRef(), o, nil)
+	assert.Equal(t, types.HealthProgressing, h.Health)
+	assert.Equal(t, "readyReplicas (1) is less then replicas (2)", h.Message)
+
+	o = buildDeployment(1, "False", "ProgressDeadlineExceeded", 2)
+	h = GetObjectHealth(nil, o.GetK8sRef(), o, nil)
+	assert.Equal(t, types.HealthDegraded, h.Health)
+	assert.Equal(t, "deadline exceeded", h.Message)
+
+	// the controller did not see the current generation yet, so the condition is outdated
+	o = buildDeployment(1, "False", "ProgressDeadlineExceeded", 1)
+	h = GetObjectHealth(nil, o.GetK8sRef(), o, nil)
+	assert.Equal(t, types.HealthProgressing, h.Health)
+
+	h = GetObjectHealth(nil, o.GetK8sRef(), nil, nil)
+	assert.Equal(t, types.HealthMissing, h.Health)
+}
+
+func TestAggregateHealth(t *testing.T) {
+	assert.Equal(t, types.HealthHealthy, AggregateHealth(nil))
+	assert.Equal(t, types.HealthProgressing, AggregateHealth([]types.ObjectHealth{
+		{Health: types.HealthHealthy},
+		{Health: types.HealthProgressing},
+	}))
+	assert.Equal(t, types.HealthMissing, AggregateHealth([]types.ObjectHealth{
+		{Health: types.HealthMissing},
+		{Health: types.HealthProgressing},
+	}))
+	assert.Equal(t, types.HealthDegraded, AggregateHealth([]types.ObjectHealth{
+		{Health: types.HealthDegraded},
+		{Health: types.HealthMissing},
+		{Health: types.HealthHealthy},
+	}))
+}
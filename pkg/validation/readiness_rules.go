@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ReadinessRules holds the compiled readiness rules of a project. A nil *ReadinessRules is valid and contains no rules.
+type ReadinessRules struct {
+	rules []*readinessRule
+}
+
+type readinessRule struct {
+	group   *string
+	version *string
+	kind    string
+
+	ready      *uo.MyJsonPath
+	failed     *uo.MyJsonPath
+	inProgress *uo.MyJsonPath
+	message    *uo.MyJsonPath
+}
+
+func NewReadinessRules(configs []*types.ReadinessRuleConfig) (*ReadinessRules, error) {
+	ret := &ReadinessRules{}
+	for _, c := range configs {
+		r := &readinessRule{
+			group:   c.Group,
+			version: c.Version,
+			kind:    c.Kind,
+		}
+
+		parse := func(p string) (*uo.MyJsonPath, error) {
+			if p == "" {
+				return nil, nil
+			}
+			j, err := uo.NewMyJsonPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid readiness rule for kind %s: %w", c.Kind, err)
+			}
+			return j, nil
+		}
+
+		var err error
+		if r.ready, err = parse(c.Ready); err != nil {
+			return nil, err
+		}
+		if r.failed, err = parse(c.Failed); err != nil {
+			return nil, err
+		}
+		if r.inProgress, err = parse(c.InProgress); err != nil {
+			return nil, err
+		}
+		if r.message, err = parse(c.Message); err != nil {
+			return nil, err
+		}
+		ret.rules = append(ret.rules, r)
+	}
+	return ret, nil
+}
+
+// find returns the first rule that matches the given GVK
+func (r *ReadinessRules) find(gvk schema.GroupVersionKind) *readinessRule {
+	if r == nil {
+		return nil
+	}
+	for _, rule := range r.rules {
+		if rule.kind != gvk.Kind {
+			continue
+		}
+		if rule.group != nil && *rule.group != gvk.Group {
+			continue
+		}
+		if rule.version != nil && *rule.version != gvk.Version {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// matches returns true if the JSONPath matches at least one value that is not false, null or an empty string
+func matches(j *uo.MyJsonPath, o *uo.UnstructuredObject) bool {
+	if j == nil {
+		return false
+	}
+	for _, v := range j.Get(o) {
+		switch x := v.(type) {
+		case nil:
+			continue
+		case bool:
+			if !x {
+				continue
+			}
+		case string:
+			if x == "" {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (r *readinessRule) getMessage(o *uo.UnstructuredObject, def string) string {
+	if r.message == nil {
+		return def
+	}
+	v, ok := r.message.GetFirst(o)
+	if !ok || v == nil || v == "" {
+		return def
+	}
+	return fmt.Sprint(v)
+}
+
+// evaluate checks the conditions in the order failed, inProgress and ready. An object that matches none of the
+// conditions is considered to be not ready.
+func (r *readinessRule) evaluate(o *uo.UnstructuredObject, addError func(string), addNotReady func(string)) {
+	if matches(r.failed, o) {
+		addError(r.getMessage(o, "Failed"))
+		return
+	}
+	if matches(r.inProgress, o) {
+		addNotReady(r.getMessage(o, "In progress"))
+		return
+	}
+	if matches(r.ready, o) {
+		return
+	}
+	addNotReady(r.getMessage(o, "Not ready"))
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildCertificate(ready string, reason string, message string) *uo.UnstructuredObject {
+	o := uo.FromStringMust(`
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: c1
+  namespace: default
+status:
+  conditions:
+  - type: Ready
+`)
+	_ = o.SetNestedField(ready, "status", "conditions", 0, "status")
+	_ = o.SetNestedField(reason, "status", "conditions", 0, "reason")
+	_ = o.SetNestedField(message, "status", "conditions", 0, "message")
+	return o
+}
+
+func TestReadinessRules(t *testing.T) {
+	group := "cert-manager.io"
+	rules, err := NewReadinessRules([]*types.ReadinessRuleConfig{
+		{
+			Group:      &group,
+			Kind:       "Certificate",
+			Ready:      `$.status.conditions[?(@.type == 'Ready' && @.status == 'True')]`,
+			Failed:     `$.status.conditions[?(@.type == 'Ready' && @.reason == 'Failed')]`,
+			InProgress: `$.status.conditions[?(@.type == 'Ready' && @.reason == 'Issuing')]`,
+			Message:    `$.status.conditions[?(@.type == 'Ready')].message`,
+		},
+	})
+	assert.NoError(t, err)
+
+	r := ValidateObject(nil, buildCertificate("True", "Ready", "issued"), true, false, rules)
+	assert.True(t, r.Ready)
+	assert.Empty(t, r.Errors)
+
+	r = ValidateObject(nil, buildCertificate("False", "Failed", "issuer not found"), true, false, rules)
+	assert.False(t, r.Ready)
+	assert.Len(t, r.Errors, 1)
+	assert.Equal(t, "issuer not found", r.Errors[0].Error)
+
+	r = ValidateObject(nil, buildCertificate("False", "Issuing", "issuing certificate"), false, false, rules)
+	assert.False(t, r.Ready)
+	assert.Empty(t, r.Errors)
+	assert.Len(t, r.Warnings, 1)
+	assert.Equal(t, "issuing certificate", r.Warnings[0].Error)
+
+	// objects without status are handled by the rule as well
+	o := buildCertificate("", "", "")
+	o.RemoveNestedField("status")
+	r = ValidateObject(nil, o, false, true, rules)
+	assert.False(t, r.Ready)
+	assert.Equal(t, "Not ready", r.Warnings[0].Error)
+
+	// other groups are not affected by the rule
+	o = buildCertificate("False", "Failed", "")
+	o.SetK8sGVK(o.GetK8sGVK().GroupVersion().WithKind("Other"))
+	r = ValidateObject(nil, o, true, false, rules)
+	assert.True(t, r.Ready)
+}
+
+func TestReadinessRulesInvalid(t *testing.T) {
+	_, err := NewReadinessRules([]*types.ReadinessRuleConfig{
+		{Kind: "Certificate", Ready: "$.status[?("},
+	})
+	assert.Error(t, err)
+}
@@ -45,7 +45,7 @@ const (
 	reactNotReady
 )
 
-func ValidateObject(k *k8s.K8sCluster, o *uo.UnstructuredObject, notReadyIsError bool, forceStatusRequired bool) (ret types.ValidateResult) {
+func ValidateObject(k *k8s.K8sCluster, o *uo.UnstructuredObject, notReadyIsError bool, forceStatusRequired bool, rules *ReadinessRules) (ret types.ValidateResult) {
 	ref := o.GetK8sRef()
 
 	// We assume all is good in case no validation is performed
@@ -116,6 +116,12 @@ func ValidateObject(k *k8s.K8sCluster, o *uo.UnstructuredObject, notReadyIsError
 		}
 	}
 
+	// project specific rules take precedence over the built-in logic
+	if rule := rules.find(o.GetK8sGVK()); rule != nil {
+		rule.evaluate(o, addError, addNotReady)
+		return
+	}
+
 	status, _, _ := o.GetNestedObject("status")
 	if status == nil {
 		if forceStatusRequired {
@@ -290,6 +296,15 @@ func ValidateObject(k *k8s.K8sCluster, o *uo.UnstructuredObject, notReadyIsError
 			}
 		}
 	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		// the Deployment controller gave up progressing, so there is no point in waiting any longer. This is only
+		// trusted if the controller has already seen the current generation, as the condition might be outdated otherwise
+		generation, _, _ := o.GetNestedInt("metadata", "generation")
+		observedGeneration, _, _ := status.GetNestedInt("observedGeneration")
+		c := getCondition("Progressing", reactIgnore, false)
+		if c.status == "False" && c.reason == "ProgressDeadlineExceeded" && observedGeneration >= generation {
+			addError(c.getMessage("Progress deadline exceeded"))
+			return
+		}
 		specReplicas, ok, _ := o.GetNestedInt("spec", "replicas")
 		if ok && specReplicas != 0 {
 			readyReplicas := getStatusFieldInt("readyReplicas", reactNotReady, true, 0)